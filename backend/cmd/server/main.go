@@ -1,16 +1,10 @@
 package main
 
 import (
-	"go.uber.org/fx"
-	"artisan-coder/internal/app"
+	"artisan-coder/internal/cli"
 )
 
 func main() {
-	// 创建并启动 FX 应用
-	app := fx.New(
-		app.Module(),
-	)
-
-	// 运行应用（阻塞直到收到信号）
-	app.Run()
+	// 解析子命令并执行，默认启动 HTTP 服务器
+	cli.Execute()
 }
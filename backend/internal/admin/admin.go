@@ -0,0 +1,136 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"strings"
+
+	"go.uber.org/fx"
+
+	"artisan-coder/internal/config"
+	"artisan-coder/pkg/version"
+)
+
+// Module 返回管理端模块的 FX 选项
+// 管理端独立监听，默认只绑定 localhost，不经过 API 的中间件
+func Module() fx.Option {
+	return fx.Invoke(RegisterServer)
+}
+
+// ServerIn 管理端依赖组
+type ServerIn struct {
+	fx.In
+	Lifecycle fx.Lifecycle
+	Config    *config.Config
+	Watcher   *config.Watcher
+	Level     *slog.LevelVar
+	Logger    *slog.Logger
+}
+
+// RegisterServer 创建管理端 HTTP 服务器并注册生命周期钩子
+func RegisterServer(in ServerIn) {
+	cfg := in.Config.Admin
+	if !cfg.Enabled {
+		return
+	}
+
+	log := in.Logger.With("component", "admin")
+	server := &http.Server{
+		Addr:    cfg.Addr,
+		Handler: NewHandler(in.Watcher, in.Level, log),
+	}
+
+	in.Lifecycle.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			ln, err := net.Listen("tcp", server.Addr)
+			if err != nil {
+				return fmt.Errorf("failed to listen on admin addr %s: %w", server.Addr, err)
+			}
+			log.Info("Starting admin server", "addr", ln.Addr().String())
+
+			go func() {
+				if err := server.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+					log.Error("Admin server failed", "error", err)
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			return server.Shutdown(ctx)
+		},
+	})
+}
+
+// NewHandler 创建管理端路由
+func NewHandler(watcher *config.Watcher, level *slog.LevelVar, log *slog.Logger) http.Handler {
+	mux := http.NewServeMux()
+
+	// 性能分析
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+	// 运行时变量
+	mux.Handle("/debug/vars", expvar.Handler())
+
+	// 生效配置（脱敏），包含热更新后的值
+	mux.HandleFunc("GET /config", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, watcher.Current().Redacted())
+	})
+
+	// 版本信息
+	mux.HandleFunc("GET /version", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, version.Get())
+	})
+
+	// 日志级别
+	mux.HandleFunc("GET /loglevel", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, levelBody{Level: strings.ToLower(level.Level().String())})
+	})
+	mux.HandleFunc("PUT /loglevel", func(w http.ResponseWriter, r *http.Request) {
+		var body levelBody
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeJSON(w, http.StatusBadRequest, errorBody{Error: "invalid request body"})
+			return
+		}
+
+		var newLevel slog.Level
+		if err := newLevel.UnmarshalText([]byte(body.Level)); err != nil {
+			writeJSON(w, http.StatusBadRequest, errorBody{Error: err.Error()})
+			return
+		}
+
+		old := level.Level()
+		level.Set(newLevel)
+		log.Warn("Log level changed", "from", old.String(), "to", newLevel.String())
+		writeJSON(w, http.StatusOK, levelBody{Level: strings.ToLower(newLevel.String())})
+	})
+
+	return mux
+}
+
+type levelBody struct {
+	Level string `json:"level"`
+}
+
+type errorBody struct {
+	Error string `json:"error"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
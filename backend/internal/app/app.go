@@ -3,35 +3,65 @@ package app
 import (
 	"go.uber.org/fx"
 
+	"artisan-coder/internal/admin"
 	"artisan-coder/internal/config"
 	"artisan-coder/internal/database"
 	"artisan-coder/internal/handler"
+	"artisan-coder/internal/health"
+	"artisan-coder/internal/logger"
+	"artisan-coder/internal/metrics"
+	"artisan-coder/internal/ratelimit"
 	"artisan-coder/internal/repository"
 	"artisan-coder/internal/router"
 	"artisan-coder/internal/server"
 	"artisan-coder/internal/service"
+	"artisan-coder/internal/tracing"
+	"artisan-coder/pkg/clock"
+	"artisan-coder/pkg/i18n"
+	"artisan-coder/pkg/idgen"
 	"artisan-coder/pkg/jwt"
 )
 
 // Module 返回 FX 应用模块
 func Module() fx.Option {
+	return fx.Options(
+		// 配置校验保证 server.drainDelay + server.shutdownTimeout 在此之内
+		fx.StopTimeout(config.StopTimeout),
+		CoreModule(),
+
+		// 服务器层
+		server.Module(),
+		admin.Module(),
+	)
+}
+
+// CoreModule 返回除监听端口的服务器外的全部模块，最终产出 *gin.Engine
+// testkit 将其挂到 httptest.Server 上
+func CoreModule() fx.Option {
 	return fx.Options(
 		// 基础模块
 		config.Module(),
+		clock.Module(),
+		idgen.Module(),
+		logger.Module(),
+		tracing.Module(),
+		i18n.Module(),
 
 		// 数据层
 		database.Module(),
 		jwt.Module(),
 
+		// 可观测性
+		metrics.Module(),
+		health.Module(),
+
 		// 业务层
 		repository.Module(),
 		service.Module(),
 
 		// HTTP 层
+		ratelimit.Module(),
 		handler.Module(),
 		router.Module(),
-
-		// 服务器层
-		server.Module(),
 	)
 }
@@ -0,0 +1,98 @@
+package app_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/fx"
+
+	"artisan-coder/internal/testkit"
+)
+
+// newTracedKit 使用内存 span 记录器替换 TracerProvider
+func newTracedKit(t *testing.T) (*testkit.Kit, *tracetest.SpanRecorder) {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	kit := testkit.New(t, testkit.WithFx(fx.Decorate(func(trace.TracerProvider) trace.TracerProvider {
+		return tp
+	})))
+	return kit, recorder
+}
+
+// findSpan 返回最后结束的同名 span
+func findSpan(t *testing.T, spans []sdktrace.ReadOnlySpan, name string) sdktrace.ReadOnlySpan {
+	t.Helper()
+	for i := len(spans) - 1; i >= 0; i-- {
+		if spans[i].Name() == name {
+			return spans[i]
+		}
+	}
+	t.Fatalf("span %q not recorded", name)
+	return nil
+}
+
+func requireChild(t *testing.T, child, parent sdktrace.ReadOnlySpan) {
+	t.Helper()
+	if child.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("span %q is not a child of %q", child.Name(), parent.Name())
+	}
+}
+
+func TestTraceSpanTree(t *testing.T) {
+	kit, recorder := newTracedKit(t)
+	kit.Register(t, "alice", "alice@example.com", "password123")
+
+	spans := recorder.Ended()
+	server := findSpan(t, spans, "POST /api/auth/register")
+	service := findSpan(t, spans, "AuthService.Register")
+	repo := findSpan(t, spans, "UserRepository.Create")
+	query := findSpan(t, spans, "insert users")
+
+	if server.SpanKind() != trace.SpanKindServer {
+		t.Errorf("server span kind = %v", server.SpanKind())
+	}
+	if server.Parent().IsValid() {
+		t.Error("server span should be the root")
+	}
+	requireChild(t, service, server)
+	requireChild(t, repo, service)
+	requireChild(t, query, repo)
+}
+
+func TestTraceExpectedFailureIsNotError(t *testing.T) {
+	kit, recorder := newTracedKit(t)
+	kit.Register(t, "alice", "alice@example.com", "password123")
+
+	kit.Do(t, http.MethodPost, "/api/auth/login", map[string]string{
+		"email":    "alice@example.com",
+		"password": "wrong-password",
+	}).RequireStatus(t, http.StatusUnauthorized)
+	if span := findSpan(t, recorder.Ended(), "AuthService.Login"); span.Status().Code == codes.Error {
+		t.Error("invalid credentials marked as span error")
+	}
+
+	ctx := context.Background()
+	user, err := kit.UserRepository.FindByEmail(ctx, "alice@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := kit.Clock.Now()
+	user.DisabledAt = &now
+	if err := kit.UserRepository.Update(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+
+	kit.Do(t, http.MethodPost, "/api/auth/login", map[string]string{
+		"email":    "alice@example.com",
+		"password": "password123",
+	}).RequireStatus(t, http.StatusForbidden)
+	if span := findSpan(t, recorder.Ended(), "AuthService.Login"); span.Status().Code == codes.Error {
+		t.Error("disabled user login marked as span error")
+	}
+}
@@ -0,0 +1,132 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"artisan-coder/pkg/clock"
+)
+
+// Cache 键值缓存
+type Cache[K comparable, V any] interface {
+	Get(key K) (V, bool)
+	Set(key K, value V)
+	// Update 原子地修改已缓存的值，不延长过期时间；未缓存或已过期时不写入并返回 false
+	Update(key K, fn func(V) V) bool
+	Delete(key K)
+	// Purge 清空缓存
+	Purge()
+}
+
+// LRU 进程内缓存，条目数超出容量时淘汰最久未使用的条目，过期条目在读取时移除
+// 并发安全
+type LRU[K comparable, V any] struct {
+	size  int
+	ttl   time.Duration
+	clock clock.Clock
+
+	mu    sync.Mutex
+	order *list.List // 队首为最近使用
+	items map[K]*list.Element
+}
+
+type entry[K comparable, V any] struct {
+	key       K
+	value     V
+	expiresAt time.Time
+}
+
+// NewLRU 创建最多 size 个条目、每个条目有效 ttl 的缓存
+func NewLRU[K comparable, V any](size int, ttl time.Duration, clk clock.Clock) *LRU[K, V] {
+	return &LRU[K, V]{
+		size:  size,
+		ttl:   ttl,
+		clock: clk,
+		order: list.New(),
+		items: make(map[K]*list.Element, size),
+	}
+}
+
+func (c *LRU[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var zero V
+	el, ok := c.items[key]
+	if !ok {
+		return zero, false
+	}
+	e := el.Value.(*entry[K, V])
+	if !c.clock.Now().Before(e.expiresAt) {
+		c.remove(el)
+		return zero, false
+	}
+	c.order.MoveToFront(el)
+	return e.value, true
+}
+
+func (c *LRU[K, V]) Set(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expiresAt := c.clock.Now().Add(c.ttl)
+	if el, ok := c.items[key]; ok {
+		e := el.Value.(*entry[K, V])
+		e.value, e.expiresAt = value, expiresAt
+		c.order.MoveToFront(el)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&entry[K, V]{key: key, value: value, expiresAt: expiresAt})
+	for c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+}
+
+func (c *LRU[K, V]) Update(key K, fn func(V) V) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return false
+	}
+	e := el.Value.(*entry[K, V])
+	if !c.clock.Now().Before(e.expiresAt) {
+		c.remove(el)
+		return false
+	}
+	e.value = fn(e.value)
+	return true
+}
+
+func (c *LRU[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		c.remove(el)
+	}
+}
+
+func (c *LRU[K, V]) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.order.Init()
+	c.items = make(map[K]*list.Element, c.size)
+}
+
+// Len 返回当前条目数，包含尚未移除的过期条目
+func (c *LRU[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// remove 移除条目，调用方需持有锁
+func (c *LRU[K, V]) remove(el *list.Element) {
+	c.order.Remove(el)
+	delete(c.items, el.Value.(*entry[K, V]).key)
+}
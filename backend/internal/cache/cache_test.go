@@ -0,0 +1,86 @@
+package cache_test
+
+import (
+	"testing"
+	"time"
+
+	"artisan-coder/internal/cache"
+	"artisan-coder/pkg/clock"
+)
+
+func TestLRUEvictsLeastRecentlyUsed(t *testing.T) {
+	c := cache.NewLRU[string, int](2, time.Minute, clock.NewFake(time.Unix(0, 0)))
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Get("a")
+	c.Set("c", 3)
+
+	if _, ok := c.Get("b"); ok {
+		t.Error("b should have been evicted")
+	}
+	for key, want := range map[string]int{"a": 1, "c": 3} {
+		if got, ok := c.Get(key); !ok || got != want {
+			t.Errorf("Get(%q) = %d, %t", key, got, ok)
+		}
+	}
+}
+
+func TestLRUExpires(t *testing.T) {
+	clk := clock.NewFake(time.Unix(0, 0))
+	c := cache.NewLRU[string, int](10, time.Minute, clk)
+	c.Set("a", 1)
+
+	clk.Advance(59 * time.Second)
+	if _, ok := c.Get("a"); !ok {
+		t.Fatal("a expired early")
+	}
+	clk.Advance(time.Second)
+	if _, ok := c.Get("a"); ok {
+		t.Fatal("a did not expire")
+	}
+	if c.Len() != 0 {
+		t.Errorf("Len = %d, expired entry not removed", c.Len())
+	}
+}
+
+func TestLRUUpdateKeepsExpiry(t *testing.T) {
+	clk := clock.NewFake(time.Unix(0, 0))
+	c := cache.NewLRU[string, int](10, time.Minute, clk)
+	double := func(v int) int { return v * 2 }
+
+	if c.Update("missing", double) {
+		t.Error("Update inserted a missing key")
+	}
+	if _, ok := c.Get("missing"); ok {
+		t.Error("missing key present after Update")
+	}
+
+	c.Set("a", 1)
+	clk.Advance(30 * time.Second)
+	if !c.Update("a", double) {
+		t.Fatal("Update returned false for a cached key")
+	}
+	if got, _ := c.Get("a"); got != 2 {
+		t.Errorf("Get = %d, want 2", got)
+	}
+
+	clk.Advance(30 * time.Second)
+	if _, ok := c.Get("a"); ok {
+		t.Error("Update extended the expiry")
+	}
+}
+
+func TestLRUDeleteAndPurge(t *testing.T) {
+	c := cache.NewLRU[string, int](10, time.Minute, clock.NewFake(time.Unix(0, 0)))
+	c.Set("a", 1)
+	c.Set("b", 2)
+
+	c.Delete("a")
+	if _, ok := c.Get("a"); ok {
+		t.Error("a present after Delete")
+	}
+	c.Purge()
+	if c.Len() != 0 {
+		t.Errorf("Len = %d after Purge", c.Len())
+	}
+}
@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/fx"
+
+	"artisan-coder/internal/app"
+	"artisan-coder/internal/config"
+	"artisan-coder/internal/database"
+	"artisan-coder/internal/logger"
+	"artisan-coder/internal/repository"
+	"artisan-coder/internal/tracing"
+	"artisan-coder/pkg/clock"
+	"artisan-coder/pkg/idgen"
+	"artisan-coder/pkg/version"
+)
+
+// NewRootCommand 创建命令行入口
+// 不带子命令时等同于 serve，保持原有启动方式
+func NewRootCommand() *cobra.Command {
+	root := &cobra.Command{
+		Use:           "server",
+		Short:         "Artisan Coder API server",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return serve()
+		},
+	}
+
+	root.AddCommand(
+		newServeCommand(),
+		newMigrateCommand(),
+		newUserCommand(),
+		newConfigCommand(),
+		newVersionCommand(),
+	)
+	return root
+}
+
+// Execute 执行命令行，出错时以非零状态退出
+func Execute() {
+	if err := NewRootCommand().Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
+
+func newServeCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "serve",
+		Short: "Start the HTTP server",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return serve()
+		},
+	}
+}
+
+// serve 启动完整的 FX 应用（阻塞直到收到信号）
+func serve() error {
+	application := fx.New(app.Module())
+	if err := application.Err(); err != nil {
+		return err
+	}
+	application.Run()
+	return nil
+}
+
+func newVersionCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "version",
+		Short: "Print build and version information",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			info := version.Get()
+			fmt.Fprintf(cmd.OutOrStdout(), "version:    %s\ncommit:     %s\nbuild time: %s\ngo:         %s\nplatform:   %s\n",
+				info.Version, info.Commit, info.BuildTime, info.GoVersion, info.Platform)
+			return nil
+		},
+	}
+}
+
+// dataModules 运维命令使用的模块：配置、日志、数据库连接和仓储
+// 不包含 HTTP 层，也不执行自动迁移
+func dataModules() fx.Option {
+	return fx.Options(
+		config.Module(),
+		clock.Module(),
+		idgen.Module(),
+		logger.Module(),
+		tracing.Module(),
+		database.ConnectionModule(),
+		repository.Module(),
+	)
+}
+
+// runTask 构建 FX 依赖图并执行 fn，完成后按生命周期关闭资源
+// targets 为 fx.Populate 的目标指针
+func runTask(ctx context.Context, modules fx.Option, fn func(ctx context.Context) error, targets ...interface{}) error {
+	application := fx.New(
+		modules,
+		fx.Populate(targets...),
+	)
+	if err := application.Err(); err != nil {
+		return err
+	}
+
+	if err := application.Start(ctx); err != nil {
+		return err
+	}
+
+	runErr := fn(ctx)
+
+	stopCtx, cancel := context.WithTimeout(context.Background(), application.StopTimeout())
+	defer cancel()
+	if err := application.Stop(stopCtx); err != nil && runErr == nil {
+		runErr = err
+	}
+	return runErr
+}
@@ -0,0 +1,174 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"gorm.io/gorm"
+
+	"artisan-coder/internal/database"
+	"artisan-coder/internal/migrate"
+	"artisan-coder/migrations"
+)
+
+const defaultMigrationsDir = "migrations"
+
+func newMigrateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Manage database schema migrations",
+	}
+
+	// 执行的是编译进二进制的迁移文件
+	withMigrator := func(cmd *cobra.Command, fn func(ctx context.Context, m *migrate.Migrator) error) error {
+		var m *migrate.Migrator
+		return runTask(cmd.Context(), dataModules(), func(ctx context.Context) error {
+			return fn(ctx, m)
+		}, &m)
+	}
+
+	up := &cobra.Command{
+		Use:   "up",
+		Short: "Apply all pending migrations",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withMigrator(cmd, func(ctx context.Context, m *migrate.Migrator) error {
+				n, err := m.Up(ctx)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Applied %d migration(s)\n", n)
+				return nil
+			})
+		},
+	}
+
+	var steps int
+	down := &cobra.Command{
+		Use:   "down",
+		Short: "Roll back migrations",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if steps <= 0 {
+				return errors.New("--steps must be positive")
+			}
+			return withMigrator(cmd, func(ctx context.Context, m *migrate.Migrator) error {
+				n, err := m.Down(ctx, steps)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Rolled back %d migration(s)\n", n)
+				return nil
+			})
+		},
+	}
+	down.Flags().IntVar(&steps, "steps", 1, "number of migrations to roll back")
+
+	status := &cobra.Command{
+		Use:   "status",
+		Short: "Show current version and pending migrations",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withMigrator(cmd, func(ctx context.Context, m *migrate.Migrator) error {
+				s, err := m.Status(ctx)
+				if err != nil {
+					return err
+				}
+				out := cmd.OutOrStdout()
+				fmt.Fprintf(out, "current: %d\nlatest:  %d\ndirty:   %t\n", s.Current, s.Latest, s.Dirty)
+				for _, p := range s.Pending {
+					fmt.Fprintf(out, "pending: %06d_%s\n", p.Version, p.Name)
+				}
+				return nil
+			})
+		},
+	}
+
+	baseline := &cobra.Command{
+		Use:   "baseline <version>",
+		Short: "Mark an unversioned database as migrated up to version without running SQL",
+		Long: "Mark an unversioned database as migrated up to version without running SQL.\n" +
+			"Databases created by the old AutoMigrate match version 1 (users only), 2 (plus rate_limits)\n" +
+			"or 3 (plus users.role); serve names the version on startup. Run `migrate up` and `migrate verify` afterwards.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			version, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid version %q", args[0])
+			}
+			return withMigrator(cmd, func(ctx context.Context, m *migrate.Migrator) error {
+				if err := m.Baseline(ctx, version); err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Baseline set to %d\n", version)
+				return nil
+			})
+		},
+	}
+
+	verify := &cobra.Command{
+		Use:   "verify",
+		Short: "Compare the database schema with the GORM models",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var db *gorm.DB
+			return runTask(cmd.Context(), dataModules(), func(ctx context.Context) error {
+				if err := database.VerifySchema(ctx, db); err != nil {
+					return fmt.Errorf("schema does not match models:\n%w", err)
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), "Schema matches models")
+				return nil
+			}, &db)
+		},
+	}
+
+	var dir string
+	create := &cobra.Command{
+		Use:   "create <name>",
+		Short: "Create up/down migration files for every database driver",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkVersionsInSync(dir); err != nil {
+				return err
+			}
+			for _, driver := range migrations.Drivers {
+				upFile, downFile, err := migrate.Create(filepath.Join(dir, driver), args[0])
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Created %s\nCreated %s\n", upFile, downFile)
+			}
+			return nil
+		},
+	}
+
+	create.Flags().StringVar(&dir, "dir", defaultMigrationsDir, "migrations source directory")
+
+	cmd.AddCommand(up, down, status, baseline, verify, create)
+	return cmd
+}
+
+// checkVersionsInSync 确认各驱动的迁移版本一致，避免新迁移编号错开
+func checkVersionsInSync(dir string) error {
+	var latest uint64
+	for i, driver := range migrations.Drivers {
+		list, err := migrate.Load(os.DirFS(filepath.Join(dir, driver)))
+		if err != nil {
+			return err
+		}
+		var v uint64
+		if len(list) > 0 {
+			v = list[len(list)-1].Version
+		}
+		if i > 0 && v != latest {
+			return fmt.Errorf("%s migrations are at version %d, %s at %d", driver, v, migrations.Drivers[0], latest)
+		}
+		latest = v
+	}
+	return nil
+}
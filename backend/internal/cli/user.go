@@ -0,0 +1,193 @@
+package cli
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"artisan-coder/internal/models"
+	"artisan-coder/internal/repository"
+	"artisan-coder/pkg/clock"
+	"artisan-coder/pkg/password"
+)
+
+// generatedPasswordLength 未指定密码时生成的随机密码长度
+const generatedPasswordLength = 20
+
+func newUserCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "user",
+		Short: "Manage users",
+	}
+
+	var username, email, pass string
+	createAdmin := &cobra.Command{
+		Use:   "create-admin",
+		Short: "Create an administrator account",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var repo repository.UserRepository
+			return runTask(cmd.Context(), dataModules(), func(ctx context.Context) error {
+				plain, generated, err := passwordOrGenerate(pass)
+				if err != nil {
+					return err
+				}
+				hash, err := password.Hash(plain)
+				if err != nil {
+					return err
+				}
+
+				if err := ensureAvailable(ctx, repo, username, email); err != nil {
+					return err
+				}
+
+				user := &models.User{
+					Username:     username,
+					Email:        email,
+					PasswordHash: hash,
+					Role:         models.RoleAdmin,
+				}
+				if err := repo.Create(ctx, user); err != nil {
+					return err
+				}
+
+				fmt.Fprintf(cmd.OutOrStdout(), "Created admin %s (%s)\n", user.Email, user.ID)
+				if generated {
+					fmt.Fprintf(cmd.OutOrStdout(), "Generated password: %s\n", plain)
+				}
+				return nil
+			}, &repo)
+		},
+	}
+	createAdmin.Flags().StringVar(&username, "username", "", "username (required)")
+	createAdmin.Flags().StringVar(&email, "email", "", "email (required)")
+	createAdmin.Flags().StringVar(&pass, "password", "", "password, generated when empty")
+	_ = createAdmin.MarkFlagRequired("username")
+	_ = createAdmin.MarkFlagRequired("email")
+
+	var resetEmail, resetPass string
+	resetPassword := &cobra.Command{
+		Use:   "reset-password",
+		Short: "Reset a user's password",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var repo repository.UserRepository
+			return runTask(cmd.Context(), dataModules(), func(ctx context.Context) error {
+				user, err := repo.FindByEmail(ctx, resetEmail)
+				if err != nil {
+					return err
+				}
+
+				plain, generated, err := passwordOrGenerate(resetPass)
+				if err != nil {
+					return err
+				}
+				if user.PasswordHash, err = password.Hash(plain); err != nil {
+					return err
+				}
+				if err := repo.Update(ctx, user); err != nil {
+					return err
+				}
+
+				fmt.Fprintf(cmd.OutOrStdout(), "Password reset for %s\n", user.Email)
+				if generated {
+					fmt.Fprintf(cmd.OutOrStdout(), "Generated password: %s\n", plain)
+				}
+				return nil
+			}, &repo)
+		},
+	}
+	resetPassword.Flags().StringVar(&resetEmail, "email", "", "email of the user (required)")
+	resetPassword.Flags().StringVar(&resetPass, "password", "", "new password, generated when empty")
+	_ = resetPassword.MarkFlagRequired("email")
+
+	cmd.AddCommand(createAdmin, resetPassword,
+		newUserStatusCommand("disable", "Disable a user; issued tokens stop working once the user cache expires",
+			func(ctx context.Context, repo repository.UserRepository, user *models.User, now time.Time) error {
+				if user.Disabled() {
+					return nil
+				}
+				user.DisabledAt = &now
+				return repo.Update(ctx, user)
+			}),
+		newUserStatusCommand("enable", "Re-enable a disabled user",
+			func(ctx context.Context, repo repository.UserRepository, user *models.User, now time.Time) error {
+				user.DisabledAt = nil
+				return repo.Update(ctx, user)
+			}),
+		newUserStatusCommand("delete", "Soft-delete a user; the email and username stay reserved",
+			func(ctx context.Context, repo repository.UserRepository, user *models.User, now time.Time) error {
+				return repo.Delete(ctx, user.ID)
+			}),
+	)
+	return cmd
+}
+
+// newUserStatusCommand 按邮箱查找用户并执行 apply
+// 服务端按 userCache.ttl 缓存用户，未开启 notify 时变更最迟在 ttl 后对其他实例生效
+func newUserStatusCommand(use, short string, apply func(ctx context.Context, repo repository.UserRepository, user *models.User, now time.Time) error) *cobra.Command {
+	var email string
+	cmd := &cobra.Command{
+		Use:   use,
+		Short: short,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var (
+				repo repository.UserRepository
+				clk  clock.Clock
+			)
+			return runTask(cmd.Context(), dataModules(), func(ctx context.Context) error {
+				user, err := repo.FindByEmail(ctx, email)
+				if err != nil {
+					return err
+				}
+				if err := apply(ctx, repo, user, clk.Now()); err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "User %s: %s done\n", user.Email, use)
+				return nil
+			}, &repo, &clk)
+		},
+	}
+	cmd.Flags().StringVar(&email, "email", "", "email of the user (required)")
+	_ = cmd.MarkFlagRequired("email")
+	return cmd
+}
+
+// ensureAvailable 检查用户名和邮箱未被占用
+func ensureAvailable(ctx context.Context, repo repository.UserRepository, username, email string) error {
+	if _, err := repo.FindByEmail(ctx, email); err == nil {
+		return fmt.Errorf("%s: %w", email, repository.ErrEmailTaken)
+	} else if !errors.Is(err, repository.ErrUserNotFound) {
+		return err
+	}
+	if _, err := repo.FindByUsername(ctx, username); err == nil {
+		return fmt.Errorf("%s: %w", username, repository.ErrUsernameTaken)
+	} else if !errors.Is(err, repository.ErrUserNotFound) {
+		return err
+	}
+	return nil
+}
+
+// passwordOrGenerate 未提供密码时生成随机密码
+func passwordOrGenerate(pass string) (string, bool, error) {
+	if pass != "" {
+		return pass, false, nil
+	}
+
+	const alphabet = "abcdefghijkmnopqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	buf := make([]byte, generatedPasswordLength)
+	for i := range buf {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(alphabet))))
+		if err != nil {
+			return "", false, err
+		}
+		buf[i] = alphabet[n.Int64()]
+	}
+	return string(buf), true, nil
+}
@@ -2,54 +2,176 @@ package config
 
 import (
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
-	"strings"
 	"time"
 
 	"github.com/spf13/viper"
 	"go.uber.org/fx"
 )
 
+// 内置默认值，release 模式下禁止使用
+const (
+	defaultJWTSecret  = "your-secret-key-change-in-production"
+	defaultDBPassword = "artisan123"
+)
+
 type Config struct {
-	Server   ServerConfig   `mapstructure:"server"`
-	Database DatabaseConfig `mapstructure:"database"`
-	JWT      JWTConfig      `mapstructure:"jwt"`
-	CORS     CORSConfig     `mapstructure:"cors"`
+	Server    ServerConfig    `mapstructure:"server"`
+	Database  DatabaseConfig  `mapstructure:"database"`
+	JWT       JWTConfig       `mapstructure:"jwt"`
+	CORS      CORSConfig      `mapstructure:"cors"`
+	Log       LogConfig       `mapstructure:"log"`
+	RateLimit RateLimitConfig `mapstructure:"rateLimit"`
+	Metrics   MetricsConfig   `mapstructure:"metrics"`
+	Tracing   TracingConfig   `mapstructure:"tracing"`
+	Admin     AdminConfig     `mapstructure:"admin"`
+	UserCache UserCacheConfig `mapstructure:"userCache"`
+
+	Features map[string]bool `mapstructure:"features"` // 功能开关，键名不区分大小写
 }
 
 type ServerConfig struct {
 	Port            string        `mapstructure:"port"`
-	Mode            string        `mapstructure:"mode"`             // debug, release, test
+	Mode            string        `mapstructure:"mode"` // debug, release, test
 	ReadTimeout     time.Duration `mapstructure:"readTimeout"`
 	WriteTimeout    time.Duration `mapstructure:"writeTimeout"`
 	ShutdownTimeout time.Duration `mapstructure:"shutdownTimeout"`
+	DrainDelay      time.Duration `mapstructure:"drainDelay"` // 关闭前就绪检查失败的等待时间
+	TLS             TLSConfig     `mapstructure:"tls"`
+	H2C             bool          `mapstructure:"h2c"` // 未启用 TLS 时允许明文 HTTP/2
+}
+
+type TLSConfig struct {
+	CertFile     string `mapstructure:"certFile"` // 证书文件变化时自动重新加载
+	KeyFile      string `mapstructure:"keyFile"`
+	MinVersion   string `mapstructure:"minVersion"`   // 1.2, 1.3
+	ClientCAFile string `mapstructure:"clientCAFile"` // 客户端证书认证的 CA
+	ClientAuth   string `mapstructure:"clientAuth"`   // none, request, require, verify_if_given, require_and_verify
+}
+
+// Enabled 是否配置了证书
+func (c TLSConfig) Enabled() bool {
+	return c.CertFile != "" && c.KeyFile != ""
 }
 
 type DatabaseConfig struct {
+	Driver string `mapstructure:"driver"` // postgres, sqlite
+	Path   string `mapstructure:"path"`   // SQLite 文件路径，":memory:" 为内存库
+
+	URL      string `mapstructure:"url" redact:"true"` // 连接串，设置后忽略下面的连接参数
 	Host     string `mapstructure:"host"`
 	Port     string `mapstructure:"port"`
 	User     string `mapstructure:"user"`
-	Password string `mapstructure:"password"`
+	Password string `mapstructure:"password" redact:"true"`
 	DBName   string `mapstructure:"dbName"`
 	SSLMode  string `mapstructure:"sslMode"`
+
+	SlowThreshold time.Duration `mapstructure:"slowThreshold"` // 慢查询阈值，0 表示不记录
+	AutoMigrate   bool          `mapstructure:"autoMigrate"`   // 启动时执行待执行的迁移
+
+	MaxOpenConns     int           `mapstructure:"maxOpenConns"` // 0 表示不限制
+	MaxIdleConns     int           `mapstructure:"maxIdleConns"`
+	ConnMaxLifetime  time.Duration `mapstructure:"connMaxLifetime"`  // 0 表示不限制
+	ConnMaxIdleTime  time.Duration `mapstructure:"connMaxIdleTime"`  // 0 表示不限制
+	StatementTimeout time.Duration `mapstructure:"statementTimeout"` // 服务端语句超时，0 表示不限制
+	ConnectRetries   int           `mapstructure:"connectRetries"`   // 启动时连接失败的重试次数
+	ConnectBackoff   time.Duration `mapstructure:"connectBackoff"`   // 首次重试间隔，之后翻倍
+
+	Replicas []string `mapstructure:"replicas" redact:"true"` // 只读副本 DSN
 }
 
 type JWTConfig struct {
-	Secret          string        `mapstructure:"secret"`
+	Secret          string        `mapstructure:"secret" redact:"true"`
 	AccessDuration  time.Duration `mapstructure:"accessDuration"`
 	RefreshDuration time.Duration `mapstructure:"refreshDuration"`
 	Issuer          string        `mapstructure:"issuer"`
 }
 
 type CORSConfig struct {
-	AllowedOrigins []string `mapstructure:"allowedOrigins"`
-	AllowedMethods []string `mapstructure:"allowedMethods"`
-	AllowedHeaders []string `mapstructure:"allowedHeaders"`
+	AllowedOrigins   []string      `mapstructure:"allowedOrigins"` // 支持 "*" 和 https://*.example.com
+	AllowedMethods   []string      `mapstructure:"allowedMethods"`
+	AllowedHeaders   []string      `mapstructure:"allowedHeaders"`
+	ExposedHeaders   []string      `mapstructure:"exposedHeaders"`
+	AllowCredentials bool          `mapstructure:"allowCredentials"` // 对 "*" 不生效
+	MaxAge           time.Duration `mapstructure:"maxAge"`           // 预检结果缓存时间
+}
+
+type LogConfig struct {
+	Level  string `mapstructure:"level"`  // debug, info, warn, error
+	Format string `mapstructure:"format"` // json, text
+}
+
+type RateLimitConfig struct {
+	Enabled bool                     `mapstructure:"enabled"`
+	Store   string                   `mapstructure:"store"`  // memory, postgres
+	Groups  map[string]RateLimitRule `mapstructure:"groups"` // 路由组名 -> 规则
+}
+
+type RateLimitRule struct {
+	Algorithm string        `mapstructure:"algorithm"` // token_bucket, sliding_window
+	Limit     int           `mapstructure:"limit"`     // 每个窗口允许的请求数
+	Window    time.Duration `mapstructure:"window"`
+	Burst     int           `mapstructure:"burst"` // 令牌桶容量，默认等于 limit
+	Key       string        `mapstructure:"key"`   // ip, user, token
+}
+
+type MetricsConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Path    string `mapstructure:"path"`
+	Port    string `mapstructure:"port"` // 为空时挂载在主服务器上
+}
+
+type TracingConfig struct {
+	Exporter    string  `mapstructure:"exporter"` // none, stdout, otlp
+	Endpoint    string  `mapstructure:"endpoint"` // OTLP HTTP 地址，如 localhost:4318
+	Insecure    bool    `mapstructure:"insecure"`
+	ServiceName string  `mapstructure:"serviceName"`
+	SampleRatio float64 `mapstructure:"sampleRatio"` // 根 span 采样比例 0~1
+}
+
+type AdminConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Addr    string `mapstructure:"addr"` // 默认仅本机访问
+}
+
+type UserCacheConfig struct {
+	Enabled bool          `mapstructure:"enabled"`
+	Size    int           `mapstructure:"size"`   // 最多缓存的用户数
+	TTL     time.Duration `mapstructure:"ttl"`    // 未开启 notify 时，其他实例的修改最迟在此时间后可见
+	Notify  bool          `mapstructure:"notify"` // 通过 Postgres LISTEN/NOTIFY 在实例间失效缓存
+}
+
+// DSN 返回数据库连接串，优先使用 URL
+func (c DatabaseConfig) DSN() string {
+	if c.URL != "" {
+		return c.URL
+	}
+	return fmt.Sprintf(
+		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+		c.Host, c.Port, c.User, c.Password, c.DBName, c.SSLMode,
+	)
 }
 
 // Load 加载配置
 func Load() (*Config, error) {
+	v, err := newViper()
+	if err != nil {
+		return nil, err
+	}
+
+	if file := v.ConfigFileUsed(); file != "" {
+		slog.Info("Using config file", "file", file)
+	} else {
+		slog.Info("No config file found, using defaults and environment variables")
+	}
+	warnLegacyEnv()
+
+	return decode(v)
+}
+
+// newViper 读取配置文件并绑定环境变量
+func newViper() (*viper.Viper, error) {
 	v := viper.New()
 
 	// 1. 设置默认值
@@ -72,24 +194,33 @@ func Load() (*Config, error) {
 		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
 			return nil, fmt.Errorf("failed to read config file: %w", err)
 		}
-		log.Println("No config file found, using defaults and environment variables")
-	} else {
-		log.Printf("Using config file: %s", v.ConfigFileUsed())
 	}
 
 	// 4. 绑定环境变量
-	// 环境变量可以覆盖配置文件
-	// 例如：SERVER_PORT -> server.port
-	//       DB_HOST -> database.host
-	v.AutomaticEnv()
-	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	// 环境变量可以覆盖配置文件，对应关系见 EnvVars
+	// 例如：ARTISAN_SERVER_PORT -> server.port
+	//       ARTISAN_DATABASE_NAME -> database.dbName
+	//       ARTISAN_JWT_SECRET_FILE -> 读取文件内容作为 jwt.secret
+	if err := bindEnv(v); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
 
+// decode 解析并校验配置
+func decode(v *viper.Viper) (*Config, error) {
 	// 5. 解析到结构体
 	var cfg Config
 	if err := v.Unmarshal(&cfg); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
 
+	// 6. 校验，存在问题时拒绝启动
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
 	return &cfg, nil
 }
 
@@ -101,17 +232,37 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("server.readTimeout", "15s")
 	v.SetDefault("server.writeTimeout", "15s")
 	v.SetDefault("server.shutdownTimeout", "10s")
+	v.SetDefault("server.drainDelay", "0s")
+	v.SetDefault("server.tls.certFile", "")
+	v.SetDefault("server.tls.keyFile", "")
+	v.SetDefault("server.tls.minVersion", "1.2")
+	v.SetDefault("server.tls.clientCAFile", "")
+	v.SetDefault("server.tls.clientAuth", "none")
+	v.SetDefault("server.h2c", false)
 
 	// Database defaults
+	v.SetDefault("database.driver", "postgres")
+	v.SetDefault("database.path", "artisan.db")
+	v.SetDefault("database.url", "")
 	v.SetDefault("database.host", "localhost")
 	v.SetDefault("database.port", "5432")
 	v.SetDefault("database.user", "artisan")
-	v.SetDefault("database.password", "artisan123")
+	v.SetDefault("database.password", defaultDBPassword)
 	v.SetDefault("database.dbName", "artisan_coder")
 	v.SetDefault("database.sslMode", "disable")
+	v.SetDefault("database.slowThreshold", "200ms")
+	v.SetDefault("database.autoMigrate", false)
+	v.SetDefault("database.maxOpenConns", 25)
+	v.SetDefault("database.maxIdleConns", 10)
+	v.SetDefault("database.connMaxLifetime", "30m")
+	v.SetDefault("database.connMaxIdleTime", "5m")
+	v.SetDefault("database.statementTimeout", "30s")
+	v.SetDefault("database.connectRetries", 10)
+	v.SetDefault("database.connectBackoff", "500ms")
+	v.SetDefault("database.replicas", []string{})
 
 	// JWT defaults
-	v.SetDefault("jwt.secret", "your-secret-key-change-in-production")
+	v.SetDefault("jwt.secret", defaultJWTSecret)
 	v.SetDefault("jwt.accessDuration", "1h")
 	v.SetDefault("jwt.refreshDuration", "168h") // 7 days
 	v.SetDefault("jwt.issuer", "artisan-coder")
@@ -119,12 +270,64 @@ func setDefaults(v *viper.Viper) {
 	// CORS defaults
 	v.SetDefault("cors.allowedOrigins", []string{"http://localhost:5173"})
 	v.SetDefault("cors.allowedMethods", []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"})
-	v.SetDefault("cors.allowedHeaders", []string{"Origin", "Content-Type", "Authorization"})
+	v.SetDefault("cors.allowedHeaders", []string{"Origin", "Content-Type", "Authorization", "Accept-Language", "X-Request-ID"})
+	v.SetDefault("cors.exposedHeaders", []string{"Content-Length", "Content-Language", "X-Request-ID", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "Retry-After"})
+	v.SetDefault("cors.allowCredentials", true)
+	v.SetDefault("cors.maxAge", "12h")
+
+	// Rate limit defaults
+	v.SetDefault("rateLimit.enabled", true)
+	v.SetDefault("rateLimit.store", "memory")
+	v.SetDefault("rateLimit.groups", map[string]interface{}{
+		"auth": map[string]interface{}{
+			"algorithm": "sliding_window",
+			"limit":     20,
+			"window":    "1m",
+			"key":       "ip",
+		},
+		"api": map[string]interface{}{
+			"algorithm": "token_bucket",
+			"limit":     300,
+			"window":    "1m",
+			"burst":     60,
+			"key":       "user",
+		},
+	})
+
+	// Metrics defaults
+	v.SetDefault("metrics.enabled", true)
+	v.SetDefault("metrics.path", "/metrics")
+	v.SetDefault("metrics.port", "")
+
+	// Tracing defaults
+	v.SetDefault("tracing.exporter", "none")
+	v.SetDefault("tracing.endpoint", "")
+	v.SetDefault("tracing.insecure", false)
+	v.SetDefault("tracing.serviceName", "artisan-coder")
+	v.SetDefault("tracing.sampleRatio", 1.0)
+
+	// Admin defaults
+	v.SetDefault("admin.enabled", false)
+	v.SetDefault("admin.addr", "127.0.0.1:6060")
+
+	// User cache defaults
+	v.SetDefault("userCache.enabled", true)
+	v.SetDefault("userCache.size", 10000)
+	v.SetDefault("userCache.ttl", "30s")
+	v.SetDefault("userCache.notify", false)
+
+	// Feature flags
+	v.SetDefault("features", map[string]bool{})
+
+	// Log defaults
+	v.SetDefault("log.level", "info")
+	v.SetDefault("log.format", "json")
 }
 
 // Module 返回配置模块的 FX 选项
 func Module() fx.Option {
 	return fx.Provide(
 		Load,
+		NewWatcher,
 	)
 }
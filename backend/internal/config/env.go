@@ -0,0 +1,167 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// EnvPrefix 环境变量前缀
+const EnvPrefix = "ARTISAN"
+
+// fileSuffix 以文件内容作为值的环境变量后缀，如 ARTISAN_JWT_SECRET_FILE
+// 用于读取 Docker / Kubernetes 挂载的 secret 文件
+const fileSuffix = "_FILE"
+
+// EnvVar 配置项与环境变量的对应关系
+type EnvVar struct {
+	Key     string   // 配置键，如 database.dbName
+	Name    string   // 环境变量名（不含前缀），如 DATABASE_NAME
+	Aliases []string // 额外接受的完整变量名，如 DATABASE_URL
+}
+
+// FullName 带前缀的环境变量名
+func (e EnvVar) FullName() string {
+	return EnvPrefix + "_" + e.Name
+}
+
+// EnvVars 支持的环境变量，README 中的列表与此保持一致
+// 列表类配置使用逗号分隔；rateLimit.groups 只能在配置文件中设置
+var EnvVars = []EnvVar{
+	{Key: "server.port", Name: "SERVER_PORT"},
+	{Key: "server.mode", Name: "SERVER_MODE"},
+	{Key: "server.readTimeout", Name: "SERVER_READ_TIMEOUT"},
+	{Key: "server.writeTimeout", Name: "SERVER_WRITE_TIMEOUT"},
+	{Key: "server.shutdownTimeout", Name: "SERVER_SHUTDOWN_TIMEOUT"},
+	{Key: "server.drainDelay", Name: "SERVER_DRAIN_DELAY"},
+	{Key: "server.tls.certFile", Name: "SERVER_TLS_CERT_FILE"},
+	{Key: "server.tls.keyFile", Name: "SERVER_TLS_KEY_FILE"},
+	{Key: "server.tls.minVersion", Name: "SERVER_TLS_MIN_VERSION"},
+	{Key: "server.tls.clientCAFile", Name: "SERVER_TLS_CLIENT_CA_FILE"},
+	{Key: "server.tls.clientAuth", Name: "SERVER_TLS_CLIENT_AUTH"},
+	{Key: "server.h2c", Name: "SERVER_H2C"},
+
+	{Key: "database.driver", Name: "DATABASE_DRIVER"},
+	{Key: "database.path", Name: "DATABASE_PATH"},
+	{Key: "database.url", Name: "DATABASE_URL", Aliases: []string{"DATABASE_URL"}},
+	{Key: "database.host", Name: "DATABASE_HOST"},
+	{Key: "database.port", Name: "DATABASE_PORT"},
+	{Key: "database.user", Name: "DATABASE_USER"},
+	{Key: "database.password", Name: "DATABASE_PASSWORD"},
+	{Key: "database.dbName", Name: "DATABASE_NAME"},
+	{Key: "database.sslMode", Name: "DATABASE_SSL_MODE"},
+	{Key: "database.slowThreshold", Name: "DATABASE_SLOW_THRESHOLD"},
+	{Key: "database.autoMigrate", Name: "DATABASE_AUTO_MIGRATE"},
+	{Key: "database.maxOpenConns", Name: "DATABASE_MAX_OPEN_CONNS"},
+	{Key: "database.maxIdleConns", Name: "DATABASE_MAX_IDLE_CONNS"},
+	{Key: "database.connMaxLifetime", Name: "DATABASE_CONN_MAX_LIFETIME"},
+	{Key: "database.connMaxIdleTime", Name: "DATABASE_CONN_MAX_IDLE_TIME"},
+	{Key: "database.statementTimeout", Name: "DATABASE_STATEMENT_TIMEOUT"},
+	{Key: "database.connectRetries", Name: "DATABASE_CONNECT_RETRIES"},
+	{Key: "database.connectBackoff", Name: "DATABASE_CONNECT_BACKOFF"},
+	{Key: "database.replicas", Name: "DATABASE_REPLICAS"},
+
+	{Key: "jwt.secret", Name: "JWT_SECRET"},
+	{Key: "jwt.accessDuration", Name: "JWT_ACCESS_DURATION"},
+	{Key: "jwt.refreshDuration", Name: "JWT_REFRESH_DURATION"},
+	{Key: "jwt.issuer", Name: "JWT_ISSUER"},
+
+	{Key: "cors.allowedOrigins", Name: "CORS_ALLOWED_ORIGINS"},
+	{Key: "cors.allowedMethods", Name: "CORS_ALLOWED_METHODS"},
+	{Key: "cors.allowedHeaders", Name: "CORS_ALLOWED_HEADERS"},
+	{Key: "cors.exposedHeaders", Name: "CORS_EXPOSED_HEADERS"},
+	{Key: "cors.allowCredentials", Name: "CORS_ALLOW_CREDENTIALS"},
+	{Key: "cors.maxAge", Name: "CORS_MAX_AGE"},
+
+	{Key: "log.level", Name: "LOG_LEVEL"},
+	{Key: "log.format", Name: "LOG_FORMAT"},
+
+	{Key: "rateLimit.enabled", Name: "RATE_LIMIT_ENABLED"},
+	{Key: "rateLimit.store", Name: "RATE_LIMIT_STORE"},
+
+	{Key: "metrics.enabled", Name: "METRICS_ENABLED"},
+	{Key: "metrics.path", Name: "METRICS_PATH"},
+	{Key: "metrics.port", Name: "METRICS_PORT"},
+
+	{Key: "tracing.exporter", Name: "TRACING_EXPORTER"},
+	{Key: "tracing.endpoint", Name: "TRACING_ENDPOINT"},
+	{Key: "tracing.insecure", Name: "TRACING_INSECURE"},
+	{Key: "tracing.serviceName", Name: "TRACING_SERVICE_NAME"},
+	{Key: "tracing.sampleRatio", Name: "TRACING_SAMPLE_RATIO"},
+
+	{Key: "admin.enabled", Name: "ADMIN_ENABLED"},
+	{Key: "admin.addr", Name: "ADMIN_ADDR"},
+
+	{Key: "userCache.enabled", Name: "USER_CACHE_ENABLED"},
+	{Key: "userCache.size", Name: "USER_CACHE_SIZE"},
+	{Key: "userCache.ttl", Name: "USER_CACHE_TTL"},
+	{Key: "userCache.notify", Name: "USER_CACHE_NOTIFY"},
+}
+
+// legacyEnvVars 旧版 .env.example 中的变量名及替代的变量名，已不再读取
+var legacyEnvVars = [][2]string{
+	{"SERVER_PORT", "ARTISAN_SERVER_PORT"},
+	{"DB_HOST", "ARTISAN_DATABASE_HOST"},
+	{"DB_PORT", "ARTISAN_DATABASE_PORT"},
+	{"DB_USER", "ARTISAN_DATABASE_USER"},
+	{"DB_PASSWORD", "ARTISAN_DATABASE_PASSWORD"},
+	{"DB_NAME", "ARTISAN_DATABASE_NAME"},
+	{"DB_SSLMODE", "ARTISAN_DATABASE_SSL_MODE"},
+	{"JWT_SECRET", "ARTISAN_JWT_SECRET"},
+	{"FRONTEND_URL", "ARTISAN_CORS_ALLOWED_ORIGINS"},
+}
+
+// warnLegacyEnv 设置了旧版变量名时记录警告，避免配置被静默忽略
+func warnLegacyEnv() {
+	for _, pair := range legacyEnvVars {
+		if _, ok := os.LookupEnv(pair[0]); ok {
+			slog.Warn("Ignoring legacy environment variable", "name", pair[0], "use", pair[1])
+		}
+	}
+}
+
+// bindEnv 按 EnvVars 绑定环境变量
+// 设置了 <VAR>_FILE 时读取文件内容作为值，与 <VAR> 同时设置视为错误
+func bindEnv(v *viper.Viper) error {
+	for _, e := range EnvVars {
+		names := append([]string{e.FullName()}, e.Aliases...)
+
+		value, found, err := lookupFile(names)
+		if err != nil {
+			return err
+		}
+		if found {
+			v.Set(e.Key, value)
+			continue
+		}
+
+		if err := v.BindEnv(append([]string{e.Key}, names...)...); err != nil {
+			return fmt.Errorf("failed to bind env %s: %w", e.FullName(), err)
+		}
+	}
+	return nil
+}
+
+// lookupFile 查找第一个设置了 _FILE 的变量并读取文件
+func lookupFile(names []string) (string, bool, error) {
+	for _, name := range names {
+		path, ok := os.LookupEnv(name + fileSuffix)
+		if !ok || path == "" {
+			continue
+		}
+		if _, conflict := os.LookupEnv(name); conflict {
+			return "", false, fmt.Errorf("both %s and %s%s are set", name, name, fileSuffix)
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return "", false, fmt.Errorf("failed to read %s%s: %w", name, fileSuffix, err)
+		}
+		// secret 文件通常以换行结尾
+		return strings.TrimRight(string(content), "\r\n"), true, nil
+	}
+	return "", false, nil
+}
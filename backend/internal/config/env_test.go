@@ -0,0 +1,140 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// loadEnv 在没有配置文件的临时目录中按 env 加载配置
+func loadEnv(t *testing.T, env map[string]string) (*Config, error) {
+	t.Helper()
+	t.Chdir(t.TempDir())
+	t.Setenv("APP_ENV", "test")
+	for k, v := range env {
+		t.Setenv(k, v)
+	}
+	v, err := newViper()
+	if err != nil {
+		return nil, err
+	}
+	return decode(v)
+}
+
+// secretFile 写入 secret 文件并返回路径
+func secretFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "secret")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestBindEnv(t *testing.T) {
+	const secret = "0123456789abcdef0123456789abcdef"
+	const dbURL = "postgres://artisan:s3cret@db:5432/artisan_coder"
+
+	tests := []struct {
+		name  string
+		env   map[string]string
+		check func(t *testing.T, c *Config)
+	}{
+		{
+			name: "prefixed variable",
+			env:  map[string]string{"ARTISAN_DATABASE_NAME": "other_db"},
+			check: func(t *testing.T, c *Config) {
+				if c.Database.DBName != "other_db" {
+					t.Errorf("database.dbName = %q", c.Database.DBName)
+				}
+			},
+		},
+		{
+			name: "file with trailing newline",
+			env:  map[string]string{"ARTISAN_JWT_SECRET_FILE": secretFile(t, secret+"\r\n")},
+			check: func(t *testing.T, c *Config) {
+				if c.JWT.Secret != secret {
+					t.Errorf("jwt.secret = %q, want the file content without the newline", c.JWT.Secret)
+				}
+			},
+		},
+		{
+			name: "DATABASE_URL alias",
+			env:  map[string]string{"DATABASE_URL": dbURL},
+			check: func(t *testing.T, c *Config) {
+				if c.Database.URL != dbURL {
+					t.Errorf("database.url = %q, want %q", c.Database.URL, dbURL)
+				}
+			},
+		},
+		{
+			name: "DATABASE_URL_FILE alias",
+			env:  map[string]string{"DATABASE_URL_FILE": secretFile(t, dbURL+"\n")},
+			check: func(t *testing.T, c *Config) {
+				if c.Database.URL != dbURL {
+					t.Errorf("database.url = %q, want %q", c.Database.URL, dbURL)
+				}
+			},
+		},
+		{
+			name: "prefixed name wins over alias",
+			env: map[string]string{
+				"ARTISAN_DATABASE_URL": dbURL,
+				"DATABASE_URL":         "postgres://ignored@db/other",
+			},
+			check: func(t *testing.T, c *Config) {
+				if c.Database.URL != dbURL {
+					t.Errorf("database.url = %q, want %q", c.Database.URL, dbURL)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := loadEnv(t, tt.env)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.check(t, cfg)
+		})
+	}
+}
+
+func TestBindEnvErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want string
+	}{
+		{
+			name: "variable and file both set",
+			env: map[string]string{
+				"ARTISAN_JWT_SECRET":      "from-env",
+				"ARTISAN_JWT_SECRET_FILE": secretFile(t, "from-file"),
+			},
+			want: "both ARTISAN_JWT_SECRET and ARTISAN_JWT_SECRET_FILE are set",
+		},
+		{
+			name: "alias and its file both set",
+			env: map[string]string{
+				"DATABASE_URL":      "postgres://db/a",
+				"DATABASE_URL_FILE": secretFile(t, "postgres://db/b"),
+			},
+			want: "both DATABASE_URL and DATABASE_URL_FILE are set",
+		},
+		{
+			name: "missing file",
+			env:  map[string]string{"ARTISAN_JWT_SECRET_FILE": filepath.Join(t.TempDir(), "missing")},
+			want: "failed to read ARTISAN_JWT_SECRET_FILE",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadEnv(t, tt.env)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
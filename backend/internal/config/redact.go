@@ -0,0 +1,69 @@
+package config
+
+import (
+	"reflect"
+	"time"
+)
+
+// redactedValue 敏感字段的替换值
+const redactedValue = "******"
+
+// Redacted 返回脱敏后的配置，键名与配置文件一致
+// 带 redact:"true" 标签的非空字段会被替换
+func (c *Config) Redacted() map[string]interface{} {
+	return redactStruct(reflect.ValueOf(*c))
+}
+
+func redactStruct(v reflect.Value) map[string]interface{} {
+	t := v.Type()
+	out := make(map[string]interface{}, t.NumField())
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := field.Tag.Get("mapstructure")
+		if name == "" || name == "-" {
+			name = field.Name
+		}
+
+		fv := v.Field(i)
+		if field.Tag.Get("redact") == "true" {
+			if !fv.IsZero() {
+				out[name] = redactedValue
+			} else {
+				out[name] = ""
+			}
+			continue
+		}
+		out[name] = redactValue(fv)
+	}
+	return out
+}
+
+func redactValue(v reflect.Value) interface{} {
+	if d, ok := v.Interface().(time.Duration); ok {
+		return d.String()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		return redactStruct(v)
+	case reflect.Map:
+		out := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			out[iter.Key().String()] = redactValue(iter.Value())
+		}
+		return out
+	case reflect.Slice:
+		out := make([]interface{}, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out[i] = redactValue(v.Index(i))
+		}
+		return out
+	default:
+		return v.Interface()
+	}
+}
@@ -0,0 +1,315 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StopTimeout 进程停止阶段（fx 停止钩子）的总超时
+const StopTimeout = 2 * time.Minute
+
+// stopMargin server.drainDelay + server.shutdownTimeout 之外为其余停止钩子（关闭数据库、导出追踪数据等）预留的时间
+const stopMargin = 15 * time.Second
+
+// minSecretLength release 模式下 JWT 密钥的最小字节数（HS256 推荐至少 256 位）
+const minSecretLength = 32
+
+// insecureSecrets 仓库中出现过的默认密钥和密码，release 模式下禁止使用
+var insecureSecrets = map[string]struct{}{
+	defaultJWTSecret:  {},
+	defaultDBPassword: {},
+	"development-secret-key-do-not-use-in-production": {},
+	"artisan_password_change_me":                      {},
+}
+
+// ValidationError 配置校验失败，包含全部问题
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+// validator 收集校验问题
+type validator struct {
+	problems []string
+}
+
+func (v *validator) addf(format string, args ...interface{}) {
+	v.problems = append(v.problems, fmt.Sprintf(format, args...))
+}
+
+func (v *validator) required(key, value string) {
+	if strings.TrimSpace(value) == "" {
+		v.addf("%s is required", key)
+	}
+}
+
+func (v *validator) oneOf(key, value string, allowed ...string) {
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+	v.addf("%s must be one of %s, got %q", key, strings.Join(allowed, ", "), value)
+}
+
+func (v *validator) port(key, value string) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 || n > 65535 {
+		v.addf("%s must be a port number between 1 and 65535, got %q", key, value)
+	}
+}
+
+// Validate 校验配置，一次性返回所有问题
+func (c *Config) Validate() error {
+	v := &validator{}
+
+	c.validateServer(v)
+	c.validateDatabase(v)
+	c.validateJWT(v)
+	c.validateCORS(v)
+	c.validateLog(v)
+	c.validateRateLimit(v)
+	c.validateMetrics(v)
+	c.validateTracing(v)
+	c.validateAdmin(v)
+	c.validateUserCache(v)
+
+	if len(v.problems) > 0 {
+		return &ValidationError{Problems: v.problems}
+	}
+	return nil
+}
+
+// IsRelease 是否为生产模式
+func (c *Config) IsRelease() bool {
+	return c.Server.Mode == "release"
+}
+
+func (c *Config) validateServer(v *validator) {
+	s := c.Server
+	v.port("server.port", s.Port)
+	// gin.SetMode 遇到未知模式会 panic
+	v.oneOf("server.mode", s.Mode, "debug", "release", "test")
+
+	if s.ReadTimeout <= 0 {
+		v.addf("server.readTimeout must be positive, got %s", s.ReadTimeout)
+	}
+	if s.WriteTimeout < 0 {
+		v.addf("server.writeTimeout must not be negative, got %s", s.WriteTimeout)
+	}
+	if s.ShutdownTimeout <= 0 {
+		v.addf("server.shutdownTimeout must be positive, got %s", s.ShutdownTimeout)
+	}
+	if s.DrainDelay < 0 {
+		v.addf("server.drainDelay must not be negative, got %s", s.DrainDelay)
+	}
+	// 超出时 fx 在连接排空前终止停止阶段
+	if limit := StopTimeout - stopMargin; s.DrainDelay+s.ShutdownTimeout > limit {
+		v.addf("server.drainDelay (%s) + server.shutdownTimeout (%s) must not exceed %s", s.DrainDelay, s.ShutdownTimeout, limit)
+	}
+
+	t := s.TLS
+	if (t.CertFile == "") != (t.KeyFile == "") {
+		v.addf("server.tls.certFile and server.tls.keyFile must be set together")
+	}
+	v.oneOf("server.tls.minVersion", t.MinVersion, "", "1.2", "1.3")
+	v.oneOf("server.tls.clientAuth", t.ClientAuth, "", "none", "request", "require", "verify_if_given", "require_and_verify")
+	if (t.ClientAuth == "verify_if_given" || t.ClientAuth == "require_and_verify") && t.ClientCAFile == "" {
+		v.addf("server.tls.clientCAFile is required when server.tls.clientAuth is %s", t.ClientAuth)
+	}
+}
+
+func (c *Config) validateDatabase(v *validator) {
+	d := c.Database
+	v.oneOf("database.driver", d.Driver, "postgres", "sqlite")
+
+	password := d.Password
+	if d.Driver == "sqlite" {
+		v.required("database.path", d.Path)
+		if len(d.Replicas) > 0 {
+			v.addf("database.replicas is only supported by the postgres driver")
+		}
+		if c.RateLimit.Enabled && c.RateLimit.Store == "postgres" {
+			v.addf("rateLimit.store postgres requires database.driver postgres")
+		}
+		password = ""
+	} else if d.URL != "" {
+		u, err := url.Parse(d.URL)
+		if err != nil || (u.Scheme != "postgres" && u.Scheme != "postgresql") || u.Host == "" {
+			v.addf("database.url must be a postgres:// URL")
+		} else {
+			password, _ = u.User.Password()
+		}
+	} else {
+		v.required("database.host", d.Host)
+		v.port("database.port", d.Port)
+		v.required("database.user", d.User)
+		v.required("database.dbName", d.DBName)
+		v.oneOf("database.sslMode", d.SSLMode, "disable", "allow", "prefer", "require", "verify-ca", "verify-full")
+	}
+	if d.SlowThreshold < 0 {
+		v.addf("database.slowThreshold must not be negative, got %s", d.SlowThreshold)
+	}
+	if d.MaxOpenConns < 0 {
+		v.addf("database.maxOpenConns must not be negative, got %d", d.MaxOpenConns)
+	}
+	if d.MaxIdleConns < 0 {
+		v.addf("database.maxIdleConns must not be negative, got %d", d.MaxIdleConns)
+	} else if d.MaxOpenConns > 0 && d.MaxIdleConns > d.MaxOpenConns {
+		v.addf("database.maxIdleConns (%d) must not exceed database.maxOpenConns (%d)", d.MaxIdleConns, d.MaxOpenConns)
+	}
+	if d.ConnMaxLifetime < 0 {
+		v.addf("database.connMaxLifetime must not be negative, got %s", d.ConnMaxLifetime)
+	}
+	if d.ConnMaxIdleTime < 0 {
+		v.addf("database.connMaxIdleTime must not be negative, got %s", d.ConnMaxIdleTime)
+	}
+	if d.StatementTimeout < 0 {
+		v.addf("database.statementTimeout must not be negative, got %s", d.StatementTimeout)
+	}
+	if d.ConnectRetries < 0 {
+		v.addf("database.connectRetries must not be negative, got %d", d.ConnectRetries)
+	}
+	if d.ConnectRetries > 0 && d.ConnectBackoff <= 0 {
+		v.addf("database.connectBackoff must be positive when retries are enabled, got %s", d.ConnectBackoff)
+	}
+	for i, dsn := range d.Replicas {
+		if strings.TrimSpace(dsn) == "" {
+			v.addf("database.replicas[%d] must not be empty", i)
+		}
+	}
+
+	if c.IsRelease() {
+		if _, insecure := insecureSecrets[password]; insecure {
+			v.addf("database.password uses a default value, which is not allowed in release mode")
+		}
+	}
+}
+
+func (c *Config) validateJWT(v *validator) {
+	j := c.JWT
+	v.required("jwt.secret", j.Secret)
+	v.required("jwt.issuer", j.Issuer)
+	if j.AccessDuration <= 0 {
+		v.addf("jwt.accessDuration must be positive, got %s", j.AccessDuration)
+	}
+	if j.RefreshDuration <= j.AccessDuration {
+		v.addf("jwt.refreshDuration (%s) must be longer than jwt.accessDuration (%s)", j.RefreshDuration, j.AccessDuration)
+	}
+
+	if c.IsRelease() && j.Secret != "" {
+		if _, insecure := insecureSecrets[j.Secret]; insecure {
+			v.addf("jwt.secret uses a default value, which is not allowed in release mode")
+		} else if len(j.Secret) < minSecretLength {
+			v.addf("jwt.secret must be at least %d bytes in release mode, got %d", minSecretLength, len(j.Secret))
+		}
+	}
+}
+
+func (c *Config) validateCORS(v *validator) {
+	if c.CORS.MaxAge < 0 {
+		v.addf("cors.maxAge must not be negative, got %s", c.CORS.MaxAge)
+	}
+	if len(c.CORS.AllowedMethods) == 0 {
+		v.addf("cors.allowedMethods must not be empty")
+	}
+}
+
+func (c *Config) validateLog(v *validator) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(strings.TrimSpace(c.Log.Level))); err != nil {
+		v.addf("log.level must be one of debug, info, warn, error, got %q", c.Log.Level)
+	}
+	v.oneOf("log.format", strings.ToLower(c.Log.Format), "", "json", "text")
+}
+
+func (c *Config) validateRateLimit(v *validator) {
+	r := c.RateLimit
+	if !r.Enabled {
+		return
+	}
+	v.oneOf("rateLimit.store", r.Store, "", "memory", "postgres")
+
+	names := make([]string, 0, len(r.Groups))
+	for name := range r.Groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		rule := r.Groups[name]
+		prefix := "rateLimit.groups." + name
+		v.oneOf(prefix+".algorithm", rule.Algorithm, "", "token_bucket", "sliding_window")
+		v.oneOf(prefix+".key", rule.Key, "", "ip", "user", "token")
+		if rule.Limit <= 0 {
+			v.addf("%s.limit must be positive, got %d", prefix, rule.Limit)
+		}
+		if rule.Window <= 0 {
+			v.addf("%s.window must be positive, got %s", prefix, rule.Window)
+		}
+		if rule.Burst < 0 {
+			v.addf("%s.burst must not be negative, got %d", prefix, rule.Burst)
+		}
+	}
+}
+
+func (c *Config) validateMetrics(v *validator) {
+	m := c.Metrics
+	if !m.Enabled {
+		return
+	}
+	if !strings.HasPrefix(m.Path, "/") {
+		v.addf("metrics.path must start with /, got %q", m.Path)
+	}
+	if m.Port != "" {
+		v.port("metrics.port", m.Port)
+		if m.Port == c.Server.Port {
+			v.addf("metrics.port must differ from server.port")
+		}
+	}
+}
+
+func (c *Config) validateTracing(v *validator) {
+	t := c.Tracing
+	v.oneOf("tracing.exporter", t.Exporter, "", "none", "stdout", "otlp")
+	if t.SampleRatio < 0 || t.SampleRatio > 1 {
+		v.addf("tracing.sampleRatio must be between 0 and 1, got %g", t.SampleRatio)
+	}
+}
+
+func (c *Config) validateAdmin(v *validator) {
+	a := c.Admin
+	if !a.Enabled {
+		return
+	}
+	if _, port, err := net.SplitHostPort(a.Addr); err != nil {
+		v.addf("admin.addr must be host:port, got %q", a.Addr)
+	} else {
+		v.port("admin.addr port", port)
+	}
+}
+
+func (c *Config) validateUserCache(v *validator) {
+	u := c.UserCache
+	if !u.Enabled {
+		return
+	}
+	if u.Size <= 0 {
+		v.addf("userCache.size must be positive, got %d", u.Size)
+	}
+	if u.TTL <= 0 {
+		v.addf("userCache.ttl must be positive, got %s", u.TTL)
+	}
+	if u.Notify && c.Database.Driver == "sqlite" {
+		v.addf("userCache.notify requires database.driver postgres")
+	}
+}
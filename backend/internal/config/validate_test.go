@@ -0,0 +1,204 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// defaultConfig 只含默认值的配置，能通过校验
+func defaultConfig(t *testing.T) *Config {
+	t.Helper()
+	v := viper.New()
+	setDefaults(v)
+	var cfg Config
+	if err := v.Unmarshal(&cfg); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("default config is invalid: %v", err)
+	}
+	return &cfg
+}
+
+// problems 返回 Validate 报告的问题
+func problems(t *testing.T, cfg *Config) []string {
+	t.Helper()
+	err := cfg.Validate()
+	if err == nil {
+		return nil
+	}
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("Validate returned %T, want *ValidationError", err)
+	}
+	return verr.Problems
+}
+
+func TestValidateStopBudget(t *testing.T) {
+	limit := StopTimeout - stopMargin
+	tests := []struct {
+		name     string
+		drain    time.Duration
+		shutdown time.Duration
+		wantErr  bool
+	}{
+		{name: "defaults", drain: 0, shutdown: 10 * time.Second},
+		{name: "at limit", drain: limit - 30*time.Second, shutdown: 30 * time.Second},
+		{name: "over limit", drain: 90 * time.Second, shutdown: 60 * time.Second, wantErr: true},
+		{name: "shutdown alone over limit", shutdown: StopTimeout, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := defaultConfig(t)
+			cfg.Server.DrainDelay = tt.drain
+			cfg.Server.ShutdownTimeout = tt.shutdown
+
+			got := problems(t, cfg)
+			if !tt.wantErr {
+				if len(got) != 0 {
+					t.Errorf("unexpected problems: %q", got)
+				}
+				return
+			}
+			if len(got) != 1 || !strings.Contains(got[0], "server.drainDelay") {
+				t.Errorf("problems = %q, want the stop budget error", got)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	const strongSecret = "0123456789abcdef0123456789abcdef"
+	tests := []struct {
+		name    string
+		modify  func(c *Config)
+		release bool
+		want    string // 期望的问题片段，为空表示通过
+	}{
+		{name: "defaults in debug mode", modify: func(c *Config) {}},
+		{name: "release", release: true, modify: func(c *Config) {}},
+		{
+			name:    "default jwt secret in release",
+			release: true,
+			modify:  func(c *Config) { c.JWT.Secret = defaultJWTSecret },
+			want:    "jwt.secret uses a default value",
+		},
+		{
+			name:    "short jwt secret in release",
+			release: true,
+			modify:  func(c *Config) { c.JWT.Secret = "too-short" },
+			want:    "jwt.secret must be at least 32 bytes",
+		},
+		{
+			name:   "short jwt secret in debug",
+			modify: func(c *Config) { c.JWT.Secret = "too-short" },
+		},
+		{
+			name:    "default database password in release",
+			release: true,
+			modify:  func(c *Config) { c.Database.Password = defaultDBPassword },
+			want:    "database.password uses a default value",
+		},
+		{
+			name:    "default password in database url",
+			release: true,
+			modify: func(c *Config) {
+				c.Database.URL = "postgres://artisan:" + defaultDBPassword + "@db:5432/artisan_coder"
+			},
+			want: "database.password uses a default value",
+		},
+		{
+			name:    "database url overrides the default password field",
+			release: true,
+			modify: func(c *Config) {
+				c.Database.Password = defaultDBPassword
+				c.Database.URL = "postgres://artisan:s3cret@db:5432/artisan_coder"
+			},
+		},
+		{
+			name:   "invalid database url",
+			modify: func(c *Config) { c.Database.URL = "mysql://db/artisan" },
+			want:   "database.url must be a postgres:// URL",
+		},
+		{
+			name: "sqlite with replicas",
+			modify: func(c *Config) {
+				c.Database.Driver = "sqlite"
+				c.Database.Replicas = []string{"postgres://replica/artisan"}
+			},
+			want: "database.replicas is only supported by the postgres driver",
+		},
+		{
+			name: "sqlite with postgres rate limit store",
+			modify: func(c *Config) {
+				c.Database.Driver = "sqlite"
+				c.RateLimit.Store = "postgres"
+			},
+			want: "rateLimit.store postgres requires database.driver postgres",
+		},
+		{
+			name: "sqlite ignores the default password",
+			modify: func(c *Config) {
+				c.Database.Driver = "sqlite"
+				c.Database.Password = defaultDBPassword
+			},
+			release: true,
+		},
+		{
+			name: "maxIdleConns above maxOpenConns",
+			modify: func(c *Config) {
+				c.Database.MaxOpenConns = 5
+				c.Database.MaxIdleConns = 10
+			},
+			want: "database.maxIdleConns (10) must not exceed database.maxOpenConns (5)",
+		},
+		{
+			name: "maxIdleConns with unlimited maxOpenConns",
+			modify: func(c *Config) {
+				c.Database.MaxOpenConns = 0
+				c.Database.MaxIdleConns = 10
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := defaultConfig(t)
+			if tt.release {
+				cfg.Server.Mode = "release"
+				cfg.JWT.Secret = strongSecret
+				cfg.Database.Password = "s3cret"
+			}
+			tt.modify(cfg)
+
+			got := problems(t, cfg)
+			if tt.want == "" {
+				if len(got) != 0 {
+					t.Errorf("unexpected problems: %q", got)
+				}
+				return
+			}
+			if len(got) != 1 || !strings.Contains(got[0], tt.want) {
+				t.Errorf("problems = %q, want one containing %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateReportsAllProblems(t *testing.T) {
+	cfg := defaultConfig(t)
+	cfg.Server.Port = "0"
+	cfg.Log.Level = "verbose"
+	cfg.JWT.Issuer = ""
+
+	err := cfg.Validate()
+	if got := problems(t, cfg); len(got) != 3 {
+		t.Errorf("problems = %q, want 3", got)
+	}
+	if err == nil || !strings.HasPrefix(err.Error(), "invalid configuration:\n  - ") {
+		t.Errorf("Error() = %q", err)
+	}
+}
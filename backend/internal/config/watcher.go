@@ -0,0 +1,227 @@
+package config
+
+import (
+	"context"
+	"log/slog"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+	"go.uber.org/fx"
+)
+
+// reloadableKeys 支持热更新的配置键，其余配置变化需要重启
+var reloadableKeys = []string{
+	"cors.allowedOrigins",
+	"log.level",
+	"rateLimit.enabled",
+	"rateLimit.groups",
+	"features",
+}
+
+// reloadDebounce 配置文件变化后的合并等待时间
+// 编辑器保存时可能先截断再写入，避免读到不完整的文件
+const reloadDebounce = 500 * time.Millisecond
+
+// Subscriber 配置变化回调，old 和 new 均不可修改
+type Subscriber func(old, new *Config)
+
+// Watcher 监听配置文件变化，热更新白名单内的配置并通知订阅者
+// 新配置校验失败时保留原配置
+type Watcher struct {
+	v       *viper.Viper
+	log     *slog.Logger
+	current atomic.Pointer[Config]
+	stopped atomic.Bool
+
+	mu          sync.Mutex
+	subscribers []Subscriber
+	timer       *time.Timer
+
+	// reloadMu 串行执行 reload；通知订阅者时不持有 mu，订阅者可以调用 Subscribe
+	reloadMu sync.Mutex
+	lastSeen *Config // 最近一次从文件读取的完整配置，包括未生效的需重启配置
+}
+
+// NewWatcher 创建配置监听器，启动后开始监听配置文件
+func NewWatcher(lc fx.Lifecycle, cfg *Config, log *slog.Logger) (*Watcher, error) {
+	v, err := newViper()
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Watcher{v: v, log: log.With("component", "config"), lastSeen: cfg}
+	w.current.Store(cfg)
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			if v.ConfigFileUsed() == "" {
+				w.log.Debug("No config file to watch")
+				return nil
+			}
+			v.OnConfigChange(func(fsnotify.Event) { w.schedule() })
+			v.WatchConfig()
+			w.log.Info("Watching config file", "file", v.ConfigFileUsed())
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			// viper 的监听协程无法停止，停止后忽略后续事件
+			w.stopped.Store(true)
+			w.mu.Lock()
+			if w.timer != nil {
+				w.timer.Stop()
+			}
+			w.mu.Unlock()
+			return nil
+		},
+	})
+
+	return w, nil
+}
+
+// Current 返回当前生效的配置
+func (w *Watcher) Current() *Config {
+	return w.current.Load()
+}
+
+// Feature 返回功能开关状态，未配置时为 false
+func (w *Watcher) Feature(name string) bool {
+	return w.Current().Features[strings.ToLower(name)]
+}
+
+// Subscribe 注册配置变化回调
+func (w *Watcher) Subscribe(fn Subscriber) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.subscribers = append(w.subscribers, fn)
+}
+
+// schedule 合并短时间内的多次变化后再重新加载
+func (w *Watcher) schedule() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+	w.timer = time.AfterFunc(reloadDebounce, w.reload)
+}
+
+// reload 重新读取配置文件和环境变量，合并可热更新的部分后通知订阅者
+// 使用新的 viper 实例，不与 viper 的监听协程共享状态
+// 与上次读取的文件内容对比，需重启的修改只提示一次
+func (w *Watcher) reload() {
+	if w.stopped.Load() {
+		return
+	}
+
+	w.reloadMu.Lock()
+	defer w.reloadMu.Unlock()
+
+	v, err := newViper()
+	if err != nil {
+		w.log.Error("Config reload rejected, keeping previous config", "error", err)
+		return
+	}
+	fresh, err := decode(v)
+	if err != nil {
+		w.log.Error("Config reload rejected, keeping previous config", "error", err)
+		return
+	}
+
+	changed := false
+	for _, change := range diff(w.lastSeen, fresh) {
+		if !isReloadable(change.key) {
+			w.log.Warn("Config change requires restart", "key", change.key)
+			continue
+		}
+		changed = true
+		w.log.Info("Config changed", "key", change.key, "old", change.old, "new", change.new)
+	}
+	w.lastSeen = fresh
+	if !changed {
+		return
+	}
+
+	old := w.Current()
+	next := *old
+	next.CORS.AllowedOrigins = fresh.CORS.AllowedOrigins
+	next.Log.Level = fresh.Log.Level
+	next.RateLimit.Enabled = fresh.RateLimit.Enabled
+	next.RateLimit.Groups = fresh.RateLimit.Groups
+	next.Features = fresh.Features
+	w.current.Store(&next)
+
+	w.mu.Lock()
+	subscribers := w.subscribers
+	w.mu.Unlock()
+	for _, fn := range subscribers {
+		fn(old, &next)
+	}
+}
+
+func isReloadable(key string) bool {
+	for _, prefix := range reloadableKeys {
+		if key == prefix || strings.HasPrefix(key, prefix+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// change 单个配置键的变化
+type change struct {
+	key      string
+	old, new interface{}
+}
+
+// diff 按配置键对比两份脱敏后的配置
+func diff(a, b *Config) []change {
+	before := make(map[string]interface{})
+	after := make(map[string]interface{})
+	flatten("", a.Redacted(), before)
+	flatten("", b.Redacted(), after)
+
+	keys := make(map[string]struct{}, len(before))
+	for k := range before {
+		keys[k] = struct{}{}
+	}
+	for k := range after {
+		keys[k] = struct{}{}
+	}
+
+	var changes []change
+	for k := range keys {
+		if !reflect.DeepEqual(before[k], after[k]) {
+			changes = append(changes, change{key: k, old: format(before[k]), new: format(after[k])})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].key < changes[j].key })
+	return changes
+}
+
+func flatten(prefix string, m map[string]interface{}, out map[string]interface{}) {
+	for k, v := range m {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		if nested, ok := v.(map[string]interface{}); ok {
+			flatten(key, nested, out)
+			continue
+		}
+		out[key] = v
+	}
+}
+
+func format(v interface{}) interface{} {
+	if v == nil {
+		return "<unset>"
+	}
+	return v
+}
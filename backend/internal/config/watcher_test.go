@@ -0,0 +1,205 @@
+package config
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/fx/fxtest"
+)
+
+// writeConfig 写入 configs/config.test.yaml，newViper 在 APP_ENV=test 时读取它
+func writeConfig(t *testing.T, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join("configs", "config.test.yaml"), []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// newTestWatcher 在临时目录中加载 content 并创建监听器，不启动文件监听
+func newTestWatcher(t *testing.T, content string) (*Watcher, *bytes.Buffer) {
+	t.Helper()
+	t.Chdir(t.TempDir())
+	t.Setenv("APP_ENV", "test")
+	if err := os.Mkdir("configs", 0o700); err != nil {
+		t.Fatal(err)
+	}
+	writeConfig(t, content)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var logs bytes.Buffer
+	w, err := NewWatcher(fxtest.NewLifecycle(t), cfg, slog.New(slog.NewTextHandler(&logs, nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return w, &logs
+}
+
+// reloadWithin 执行 reload，超时视为死锁
+func reloadWithin(t *testing.T, w *Watcher) {
+	t.Helper()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		w.reload()
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("reload did not return")
+	}
+}
+
+func TestWatcherReload(t *testing.T) {
+	w, logs := newTestWatcher(t, `
+server:
+  port: "8080"
+cors:
+  allowedOrigins: ["http://a.example"]
+`)
+	var notified [][2]*Config
+	w.Subscribe(func(old, new *Config) {
+		notified = append(notified, [2]*Config{old, new})
+		// 订阅者回调中访问监听器不会死锁
+		w.Subscribe(func(old, new *Config) {})
+	})
+	restartWarnings := func() int {
+		return strings.Count(logs.String(), "Config change requires restart")
+	}
+
+	// 只修改需重启的配置：提示一次，不生效也不通知
+	writeConfig(t, `
+server:
+  port: "9090"
+cors:
+  allowedOrigins: ["http://a.example"]
+`)
+	reloadWithin(t, w)
+	if len(notified) != 0 {
+		t.Fatalf("subscribers notified %d times for a restart-only change", len(notified))
+	}
+	if got := w.Current().Server.Port; got != "8080" {
+		t.Errorf("server.port = %q, want the startup value 8080", got)
+	}
+	if n := restartWarnings(); n != 1 {
+		t.Errorf("restart warnings = %d, want 1", n)
+	}
+
+	// 可热更新的配置生效；未变的需重启配置不再提示
+	writeConfig(t, `
+server:
+  port: "9090"
+cors:
+  allowedOrigins: ["http://b.example"]
+`)
+	reloadWithin(t, w)
+	if len(notified) != 1 {
+		t.Fatalf("subscribers notified %d times, want 1", len(notified))
+	}
+	old, next := notified[0][0], notified[0][1]
+	if want := []string{"http://a.example"}; !reflect.DeepEqual(old.CORS.AllowedOrigins, want) {
+		t.Errorf("old origins = %v, want %v", old.CORS.AllowedOrigins, want)
+	}
+	if want := []string{"http://b.example"}; !reflect.DeepEqual(next.CORS.AllowedOrigins, want) {
+		t.Errorf("new origins = %v, want %v", next.CORS.AllowedOrigins, want)
+	}
+	if w.Current() != next {
+		t.Error("Current does not return the notified config")
+	}
+	if next.Server.Port != "8080" {
+		t.Errorf("server.port = %q after reload, want 8080 until restart", next.Server.Port)
+	}
+	if n := restartWarnings(); n != 1 {
+		t.Errorf("restart warnings = %d after an unrelated reload, want 1", n)
+	}
+
+	// 内容未变：不通知
+	reloadWithin(t, w)
+	if len(notified) != 1 {
+		t.Errorf("subscribers notified %d times for an unchanged file, want 1", len(notified))
+	}
+}
+
+func TestWatcherReloadRejectsInvalidConfig(t *testing.T) {
+	w, logs := newTestWatcher(t, `
+log:
+  level: info
+`)
+	before := w.Current()
+
+	writeConfig(t, `
+log:
+  level: verbose
+`)
+	reloadWithin(t, w)
+	if w.Current() != before {
+		t.Error("invalid config replaced the current config")
+	}
+	if !strings.Contains(logs.String(), "Config reload rejected") {
+		t.Errorf("missing rejection log:\n%s", logs)
+	}
+}
+
+func TestIsReloadable(t *testing.T) {
+	tests := []struct {
+		key  string
+		want bool
+	}{
+		{"cors.allowedOrigins", true},
+		{"log.level", true},
+		{"rateLimit.enabled", true},
+		{"rateLimit.groups.auth.limit", true},
+		{"features.signup", true},
+		{"cors.allowedMethods", false},
+		{"log.format", false},
+		{"rateLimit.store", false},
+		{"rateLimit.groupsExtra", false},
+		{"server.port", false},
+		{"featuresX", false},
+	}
+	for _, tt := range tests {
+		if got := isReloadable(tt.key); got != tt.want {
+			t.Errorf("isReloadable(%q) = %t, want %t", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	a := &Config{}
+	a.Server.Port = "8080"
+	a.JWT.Secret = "old-secret"
+	a.Features = map[string]bool{"signup": true}
+
+	b := &Config{}
+	b.Server.Port = "9090"
+	b.JWT.Secret = "new-secret"
+	b.Features = map[string]bool{"signup": true, "beta": true}
+
+	changes := diff(a, b)
+	keys := make([]string, 0, len(changes))
+	for _, c := range changes {
+		keys = append(keys, c.key)
+		if c.key == "jwt.secret" && (c.old == "old-secret" || c.new == "new-secret") {
+			t.Errorf("diff leaks the secret: %+v", c)
+		}
+	}
+	want := []string{"features.beta", "server.port"}
+	if !reflect.DeepEqual(keys, want) {
+		t.Errorf("changed keys = %v, want %v", keys, want)
+	}
+	if changes[0].old != "<unset>" || changes[0].new != true {
+		t.Errorf("features.beta change = %+v, want <unset> -> true", changes[0])
+	}
+
+	if changes := diff(a, a); len(changes) != 0 {
+		t.Errorf("diff of identical configs = %+v", changes)
+	}
+}
@@ -2,71 +2,280 @@ package database
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"time"
 
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/fx"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+	"gorm.io/plugin/opentelemetry/tracing"
 
 	"artisan-coder/internal/config"
+	"artisan-coder/internal/health"
+	"artisan-coder/internal/logger"
+	"artisan-coder/internal/migrate"
 	"artisan-coder/internal/models"
+	"artisan-coder/migrations"
+	"artisan-coder/pkg/clock"
+	"artisan-coder/pkg/idgen"
 )
 
 // Module 返回数据库模块的 FX 选项
 func Module() fx.Option {
 	return fx.Options(
-		fx.Provide(NewDB),
+		ConnectionModule(),
+		fx.Invoke(Migrate),
+	)
+}
+
+// ConnectionModule 仅提供数据库连接，不执行表结构变更
+// 供迁移等运维命令使用
+func ConnectionModule() fx.Option {
+	return fx.Options(
+		fx.Provide(
+			NewDB,
+			NewMigrator,
+			health.AsChecker(NewPingChecker),
+			health.AsChecker(NewMigrationChecker),
+		),
 		fx.Invoke(RegisterHooks),
 	)
 }
 
+// 支持的数据库驱动
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+// DBIn 数据库连接的依赖
+type DBIn struct {
+	fx.In
+	Lifecycle fx.Lifecycle
+	Config    *config.Config
+	Logger    *slog.Logger
+	Tracer    trace.TracerProvider
+	Clock     clock.Clock
+	IDs       idgen.IDGenerator
+}
+
 // NewDB 创建数据库连接
-func NewDB(cfg *config.Config) (*gorm.DB, error) {
-	dsn := fmt.Sprintf(
-		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-		cfg.Database.Host,
-		cfg.Database.Port,
-		cfg.Database.User,
-		cfg.Database.Password,
-		cfg.Database.DBName,
-		cfg.Database.SSLMode,
+// Postgres 启动时等待数据库就绪；配置了只读副本时，users 表的读操作路由到副本
+// created_at、updated_at 和 UUID 主键分别取自注入的 Clock 和 IDGenerator
+func NewDB(in DBIn) (*gorm.DB, error) {
+	lc, cfg, log := in.Lifecycle, in.Config, in.Logger
+	gormConfig := &gorm.Config{
+		// 约束冲突由仓储翻译为领域错误，不记为 error
+		Logger: logger.NewGormLogger(log, cfg.Database.SlowThreshold, IsConstraintError),
+		// 约束冲突翻译为 *ConstraintError，见 translator
+		TranslateError: true,
+		// 与 GORM 默认的 time.Now().Local() 一致
+		NowFunc: func() time.Time { return in.Clock.Now().Local() },
+	}
+
+	var (
+		db  *gorm.DB
+		err error
 	)
+	switch cfg.Database.Driver {
+	case DriverSQLite:
+		db, err = openSQLite(cfg.Database, gormConfig)
+	case "", DriverPostgres:
+		db, err = openPostgres(lc, cfg.Database, gormConfig, log)
+	default:
+		err = fmt.Errorf("unknown database driver %q", cfg.Database.Driver)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("Database connected successfully", "driver", db.Dialector.Name())
+
+	if err := db.Use(idPlugin{ids: in.IDs}); err != nil {
+		return nil, fmt.Errorf("failed to register id plugin: %w", err)
+	}
+
+	// SQL 注释中携带请求 ID
+	if err := db.Use(requestIDPlugin{}); err != nil {
+		return nil, fmt.Errorf("failed to register request id plugin: %w", err)
+	}
+
+	// 为每条 SQL 创建 span；连接池指标由 Prometheus 模块导出
+	if err := db.Use(tracing.NewPlugin(
+		tracing.WithTracerProvider(in.Tracer),
+		tracing.WithoutMetrics(),
+		tracing.WithoutQueryVariables(),
+	)); err != nil {
+		return nil, fmt.Errorf("failed to register tracing plugin: %w", err)
+	}
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	return db, nil
+}
+
+// openPostgres 连接 Postgres 并注册只读副本
+func openPostgres(lc fx.Lifecycle, cfg config.DatabaseConfig, gormConfig *gorm.Config, log *slog.Logger) (*gorm.DB, error) {
+	sqlDB, err := openPool(cfg.DSN(), cfg)
 	if err != nil {
+		return nil, err
+	}
+	if err := waitForDB(sqlDB, cfg, log); err != nil {
+		sqlDB.Close()
+		return nil, err
+	}
+
+	db, err := gorm.Open(translator{postgres.New(postgres.Config{Conn: sqlDB})}, gormConfig)
+	if err != nil {
+		sqlDB.Close()
 		return nil, fmt.Errorf("failed to connect database: %w", err)
 	}
 
-	log.Println("Database connected successfully")
+	if err := useReplicas(lc, db, cfg, log); err != nil {
+		sqlDB.Close()
+		return nil, err
+	}
+	return db, nil
+}
 
-	// 启用 uuid-ossp 扩展
-	if err := db.Exec("CREATE EXTENSION IF NOT EXISTS \"uuid-ossp\"").Error; err != nil {
-		return nil, fmt.Errorf("failed to create uuid-ossp extension: %w", err)
+// useReplicas 注册只读副本
+// 只路由 users 表的查询，迁移、限流计数等其余语句始终使用主库
+// 事务内的查询和 FOR UPDATE 也会留在主库
+func useReplicas(lc fx.Lifecycle, db *gorm.DB, cfg config.DatabaseConfig, log *slog.Logger) error {
+	if len(cfg.Replicas) == 0 {
+		return nil
 	}
 
-	// 自动迁移（开发环境）
-	// 生产环境应使用 golang-migrate
-	if cfg.Server.Mode == "debug" {
-		if err := db.AutoMigrate(&models.User{}); err != nil {
-			return nil, fmt.Errorf("failed to auto migrate: %w", err)
+	replicas := make([]gorm.Dialector, 0, len(cfg.Replicas))
+	pools := make([]*sql.DB, 0, len(cfg.Replicas))
+	for i, dsn := range cfg.Replicas {
+		pool, err := openPool(dsn, cfg)
+		if err != nil {
+			return fmt.Errorf("replica %d: %w", i, err)
 		}
-		log.Println("Database auto migration completed")
+		pools = append(pools, pool)
+		replicas = append(replicas, postgres.New(postgres.Config{Conn: pool}))
 	}
 
-	return db, nil
+	lc.Append(fx.Hook{
+		OnStop: func(context.Context) error {
+			var errs []error
+			for _, pool := range pools {
+				errs = append(errs, pool.Close())
+			}
+			return errors.Join(errs...)
+		},
+	})
+
+	if err := db.Use(dbresolver.Register(dbresolver.Config{
+		Replicas: replicas,
+		Policy:   dbresolver.RandomPolicy{},
+	}, &models.User{})); err != nil {
+		return fmt.Errorf("failed to register read replicas: %w", err)
+	}
+
+	log.Info("Read replicas registered", "count", len(replicas))
+	return nil
+}
+
+// NewMigrator 基于当前驱动的内嵌迁移文件创建迁移执行器
+func NewMigrator(db *gorm.DB, log *slog.Logger) (*migrate.Migrator, error) {
+	source, err := migrations.FS(db.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+	return migrate.New(db, source, log)
+}
+
+// Migrate 启动时检查表结构版本
+// 开启 database.autoMigrate 时先执行待执行的迁移；版本落后或处于 dirty 状态时启动失败
+func Migrate(cfg *config.Config, db *gorm.DB, m *migrate.Migrator, log *slog.Logger) error {
+	ctx := context.Background()
+
+	if err := checkUnversioned(ctx, db, m); err != nil {
+		return err
+	}
+
+	if cfg.Database.AutoMigrate {
+		n, err := m.Up(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to apply migrations: %w", err)
+		}
+		if n > 0 {
+			log.Info("Database migrations applied", "count", n)
+		}
+	}
+
+	status, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+	if status.Dirty {
+		return fmt.Errorf("database is dirty at migration %d", status.Current)
+	}
+	if status.Current < status.Latest {
+		return fmt.Errorf("database schema version %d is behind required %d, run `server migrate up` or enable database.autoMigrate",
+			status.Current, status.Latest)
+	}
+	if status.Current > status.Latest {
+		// 新版本实例已升级表结构，旧实例仍可继续运行（滚动发布）
+		log.Warn("Database schema is newer than this build", "current", status.Current, "latest", status.Latest)
+	}
+
+	log.Info("Database schema is up to date", "version", status.Current)
+	return nil
+}
+
+// checkUnversioned 已有业务表但没有迁移版本时提前失败，避免 000001 因表已存在而报错
+// 提示中的基线版本按已有的表和列推断，见 legacyVersion
+func checkUnversioned(ctx context.Context, db *gorm.DB, m *migrate.Migrator) error {
+	status, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+	db = db.WithContext(ctx)
+	if status.Current != 0 || status.Dirty || !db.Migrator().HasTable("users") {
+		return nil
+	}
+	version, err := legacyVersion(db)
+	if err != nil {
+		return fmt.Errorf("database has tables but no migration version: %w", err)
+	}
+	return fmt.Errorf("database has tables but no migration version, probably created by the old AutoMigrate; "+
+		"run `server migrate baseline %d`, then `server migrate up` and `server migrate verify`", version)
+}
+
+// legacyVersion 推断未记录版本的数据库已具备的迁移版本
+// 旧版 AutoMigrate 只创建 users 表（000001），rate_limits 表来自 000002，users.role 列来自 000003
+func legacyVersion(db *gorm.DB) (uint64, error) {
+	hasRateLimits := db.Migrator().HasTable("rate_limits")
+	hasRole := db.Migrator().HasColumn("users", "role")
+	switch {
+	case hasRateLimits && hasRole:
+		return 3, nil
+	case hasRateLimits:
+		return 2, nil
+	case hasRole:
+		return 0, errors.New("users.role exists without rate_limits, which matches no migration version; " +
+			"create rate_limits from migration 000002, then run `server migrate baseline 3`")
+	default:
+		return 1, nil
+	}
 }
 
 // RegisterHooks 注册数据库生命周期钩子
-func RegisterHooks(lc fx.Lifecycle, db *gorm.DB) {
+func RegisterHooks(lc fx.Lifecycle, db *gorm.DB, log *slog.Logger) {
 	lc.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
-			log.Println("Starting database connection...")
+			log.Info("Starting database connection...")
 			// GORM v2 自动连接，无需额外操作
 			return nil
 		},
 		OnStop: func(ctx context.Context) error {
-			log.Println("Closing database connection...")
+			log.Info("Closing database connection...")
 			sqlDB, err := db.DB()
 			if err != nil {
 				return err
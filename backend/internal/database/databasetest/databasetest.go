@@ -0,0 +1,137 @@
+// Package databasetest 测试用的数据库连接
+// 与 database.NewDB 使用相同的插件（约束翻译、ID 生成、时间），Open 还会执行全部内嵌迁移
+package databasetest
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"log/slog"
+	"net/url"
+	"os"
+	"testing"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"go.opentelemetry.io/otel/trace/noop"
+	"go.uber.org/fx/fxtest"
+	"gorm.io/gorm"
+
+	"artisan-coder/internal/config"
+	"artisan-coder/internal/database"
+	"artisan-coder/pkg/clock"
+	"artisan-coder/pkg/idgen"
+)
+
+// PostgresURLEnv Postgres 测试库连接串（URL 格式）的环境变量，未设置时跳过 Postgres 用例
+// 每次 Open 在独立 schema 中迁移，测试结束时删除
+const PostgresURLEnv = "ARTISAN_TEST_DATABASE_URL"
+
+// Drivers 测试覆盖的数据库驱动
+var Drivers = []string{database.DriverSQLite, database.DriverPostgres}
+
+// SkipUnavailable 驱动在当前环境不可用时跳过测试
+// 在按驱动划分的子测试开头调用，避免每个用例各自跳过
+func SkipUnavailable(t testing.TB, driver string) {
+	t.Helper()
+	if driver == database.DriverPostgres && os.Getenv(PostgresURLEnv) == "" {
+		t.Skipf("%s not set", PostgresURLEnv)
+	}
+}
+
+// Open 打开 driver 对应的已迁移的空数据库，测试结束时关闭
+func Open(t testing.TB, driver string) *gorm.DB {
+	t.Helper()
+
+	db := OpenUnmigrated(t, driver)
+	m, err := database.NewMigrator(db, slog.New(slog.DiscardHandler))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Up(context.Background()); err != nil {
+		t.Fatalf("migrate %s: %v", driver, err)
+	}
+	return db
+}
+
+// OpenUnmigrated 打开 driver 对应的空数据库，不执行迁移，测试结束时关闭
+func OpenUnmigrated(t testing.TB, driver string) *gorm.DB {
+	t.Helper()
+
+	cfg := &config.Config{Database: config.DatabaseConfig{
+		Driver:       driver,
+		MaxOpenConns: 4,
+		MaxIdleConns: 4,
+	}}
+	switch driver {
+	case database.DriverSQLite:
+		// 每个连接都是独立的内存库，NewDB 只保留一个连接
+		cfg.Database.Path = ":memory:"
+	case database.DriverPostgres:
+		cfg.Database.URL = postgresSchema(t)
+	default:
+		t.Fatalf("unknown database driver %q", driver)
+	}
+
+	lc := fxtest.NewLifecycle(t)
+	db, err := database.NewDB(database.DBIn{
+		Lifecycle: lc,
+		Config:    cfg,
+		Logger:    slog.New(slog.DiscardHandler),
+		Tracer:    noop.NewTracerProvider(),
+		Clock:     clock.New(),
+		IDs:       idgen.New(),
+	})
+	if err != nil {
+		t.Fatalf("open %s: %v", driver, err)
+	}
+	lc.RequireStart()
+	t.Cleanup(func() {
+		lc.RequireStop()
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
+
+// postgresSchema 创建独立 schema，返回 search_path 指向它的连接串
+// 并行运行的测试包互不影响；扩展统一安装在 public，删除 schema 时不会连带删除
+func postgresSchema(t testing.TB) string {
+	t.Helper()
+
+	SkipUnavailable(t, database.DriverPostgres)
+	base := os.Getenv(PostgresURLEnv)
+
+	admin, err := sql.Open("pgx", base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { admin.Close() })
+
+	buf := make([]byte, 6)
+	rand.Read(buf)
+	schema := "test_" + hex.EncodeToString(buf)
+	for _, stmt := range []string{
+		`CREATE EXTENSION IF NOT EXISTS "uuid-ossp" SCHEMA public`,
+		"CREATE SCHEMA " + schema,
+	} {
+		if _, err := admin.Exec(stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+	t.Cleanup(func() {
+		if _, err := admin.Exec("DROP SCHEMA " + schema + " CASCADE"); err != nil {
+			t.Errorf("drop schema %s: %v", schema, err)
+		}
+	})
+
+	u, err := url.Parse(base)
+	if err != nil {
+		t.Fatalf("%s must be a URL: %v", PostgresURLEnv, err)
+	}
+	q := u.Query()
+	q.Set("search_path", schema+",public")
+	u.RawQuery = q.Encode()
+	return u.String()
+}
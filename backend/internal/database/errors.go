@@ -0,0 +1,167 @@
+package database
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/glebarez/go-sqlite"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// ConstraintKind 约束类型
+type ConstraintKind string
+
+const (
+	ConstraintUnique     ConstraintKind = "unique"
+	ConstraintForeignKey ConstraintKind = "foreign_key"
+	ConstraintCheck      ConstraintKind = "check"
+	ConstraintNotNull    ConstraintKind = "not_null"
+)
+
+// ConstraintError 违反数据库约束
+// errors.Is 可匹配 gorm.ErrDuplicatedKey 等 GORM 错误，Unwrap 返回驱动原始错误
+type ConstraintError struct {
+	Kind       ConstraintKind
+	Constraint string // 约束名，SQLite 不提供时为空
+	Table      string
+	Column     string // 多列约束以 ", " 分隔；无法确定时为空
+	err        error
+}
+
+func (e *ConstraintError) Error() string {
+	return e.err.Error()
+}
+
+func (e *ConstraintError) Unwrap() error {
+	return e.err
+}
+
+// Is 匹配对应的 GORM 错误，兼容 TranslateError 的既有判断
+func (e *ConstraintError) Is(target error) bool {
+	switch e.Kind {
+	case ConstraintUnique:
+		return target == gorm.ErrDuplicatedKey
+	case ConstraintForeignKey:
+		return target == gorm.ErrForeignKeyViolated
+	case ConstraintCheck:
+		return target == gorm.ErrCheckConstraintViolated
+	}
+	return false
+}
+
+// IsConstraintError 判断 err 是否为翻译后的约束冲突
+func IsConstraintError(err error) bool {
+	var ce *ConstraintError
+	return errors.As(err, &ce)
+}
+
+// translator 包装方言，将约束冲突翻译为 *ConstraintError
+// GORM 只在开启 TranslateError 时调用 Translate，且自带翻译会丢失约束名和列名
+type translator struct {
+	gorm.Dialector
+}
+
+func (d translator) Translate(err error) error {
+	if ce := constraintError(err); ce != nil {
+		return ce
+	}
+	if t, ok := d.Dialector.(gorm.ErrorTranslator); ok {
+		return t.Translate(err)
+	}
+	return err
+}
+
+// SavePoint 与 RollbackTo 转发给原方言，保留嵌套事务支持
+func (d translator) SavePoint(tx *gorm.DB, name string) error {
+	if sp, ok := d.Dialector.(gorm.SavePointerDialectorInterface); ok {
+		return sp.SavePoint(tx, name)
+	}
+	return gorm.ErrUnsupportedDriver
+}
+
+func (d translator) RollbackTo(tx *gorm.DB, name string) error {
+	if sp, ok := d.Dialector.(gorm.SavePointerDialectorInterface); ok {
+		return sp.RollbackTo(tx, name)
+	}
+	return gorm.ErrUnsupportedDriver
+}
+
+// Postgres 约束错误码
+var pgConstraintKinds = map[string]ConstraintKind{
+	"23505": ConstraintUnique,
+	"23503": ConstraintForeignKey,
+	"23514": ConstraintCheck,
+	"23502": ConstraintNotNull,
+}
+
+// SQLite 扩展错误码
+var sqliteConstraintKinds = map[int]ConstraintKind{
+	sqlite3.SQLITE_CONSTRAINT_UNIQUE:     ConstraintUnique,
+	sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY: ConstraintUnique,
+	sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY: ConstraintForeignKey,
+	sqlite3.SQLITE_CONSTRAINT_CHECK:      ConstraintCheck,
+	sqlite3.SQLITE_CONSTRAINT_NOTNULL:    ConstraintNotNull,
+}
+
+var (
+	// Key (email)=(a@b.c) already exists.
+	pgDetailKey = regexp.MustCompile(`^Key \((.+?)\)=`)
+	// UNIQUE constraint failed: users.email / CHECK constraint failed: name
+	sqliteTarget = regexp.MustCompile(`constraint failed: ([^()]+)`)
+)
+
+func constraintError(err error) *ConstraintError {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		kind, ok := pgConstraintKinds[pgErr.Code]
+		if !ok {
+			return nil
+		}
+		ce := &ConstraintError{
+			Kind:       kind,
+			Constraint: pgErr.ConstraintName,
+			Table:      pgErr.TableName,
+			Column:     pgErr.ColumnName,
+			err:        err,
+		}
+		if m := pgDetailKey.FindStringSubmatch(pgErr.Detail); m != nil {
+			ce.Column = m[1]
+		}
+		return ce
+	}
+
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		kind, ok := sqliteConstraintKinds[sqliteErr.Code()]
+		if !ok {
+			return nil
+		}
+		ce := &ConstraintError{Kind: kind, err: err}
+		m := sqliteTarget.FindStringSubmatch(sqliteErr.Error())
+		if m == nil {
+			return ce
+		}
+		target := strings.TrimSpace(m[1])
+		if kind == ConstraintCheck {
+			ce.Constraint = target
+			return ce
+		}
+		// users.a, users.b
+		var columns []string
+		for _, part := range strings.Split(target, ",") {
+			table, column, found := strings.Cut(strings.TrimSpace(part), ".")
+			if !found {
+				continue
+			}
+			ce.Table = table
+			columns = append(columns, column)
+		}
+		ce.Column = strings.Join(columns, ", ")
+		return ce
+	}
+
+	return nil
+}
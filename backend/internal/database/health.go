@@ -0,0 +1,58 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"artisan-coder/internal/migrate"
+)
+
+// PingChecker 检查数据库连接
+type PingChecker struct {
+	db *gorm.DB
+}
+
+func NewPingChecker(db *gorm.DB) *PingChecker {
+	return &PingChecker{db: db}
+}
+
+func (c *PingChecker) Name() string {
+	return "database"
+}
+
+func (c *PingChecker) Check(ctx context.Context) error {
+	sqlDB, err := c.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// MigrationChecker 检查数据库迁移版本不低于内嵌迁移的最新版本
+type MigrationChecker struct {
+	migrator *migrate.Migrator
+}
+
+func NewMigrationChecker(migrator *migrate.Migrator) *MigrationChecker {
+	return &MigrationChecker{migrator: migrator}
+}
+
+func (c *MigrationChecker) Name() string {
+	return "migrations"
+}
+
+func (c *MigrationChecker) Check(ctx context.Context) error {
+	status, err := c.migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+	if status.Dirty {
+		return fmt.Errorf("migration %d is dirty", status.Current)
+	}
+	if status.Current < status.Latest {
+		return fmt.Errorf("schema version %d is behind required %d", status.Current, status.Latest)
+	}
+	return nil
+}
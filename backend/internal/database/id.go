@@ -0,0 +1,55 @@
+package database
+
+import (
+	"reflect"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"artisan-coder/pkg/idgen"
+)
+
+var uuidType = reflect.TypeOf(uuid.UUID{})
+
+// idPlugin 插入前为空的 UUID 主键生成 ID
+// 在模型的 BeforeCreate 钩子之前执行，钩子中即可读到 ID
+type idPlugin struct {
+	ids idgen.IDGenerator
+}
+
+func (idPlugin) Name() string {
+	return "artisan:id"
+}
+
+func (p idPlugin) Initialize(db *gorm.DB) error {
+	return db.Callback().Create().Before("gorm:before_create").Register("artisan:id", p.assign)
+}
+
+func (p idPlugin) assign(db *gorm.DB) {
+	if db.Statement.Schema == nil {
+		return
+	}
+	field := db.Statement.Schema.PrioritizedPrimaryField
+	if field == nil || field.FieldType != uuidType {
+		return
+	}
+
+	ctx := db.Statement.Context
+	rv := db.Statement.ReflectValue
+	set := func(v reflect.Value) {
+		if _, zero := field.ValueOf(ctx, v); zero {
+			if err := field.Set(ctx, v, p.ids.NewID()); err != nil {
+				db.AddError(err)
+			}
+		}
+	}
+
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			set(reflect.Indirect(rv.Index(i)))
+		}
+	case reflect.Struct:
+		set(rv)
+	}
+}
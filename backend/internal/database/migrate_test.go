@@ -0,0 +1,112 @@
+package database_test
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"artisan-coder/internal/config"
+	"artisan-coder/internal/database"
+	"artisan-coder/internal/database/databasetest"
+)
+
+// legacyUser 迁移体系之前 AutoMigrate 使用的用户模型
+// 当时 id 的默认值为 uuid_generate_v4()，SQLite 不支持，只在 Postgres 上补上
+// SQLite 的非整数主键允许 NULL，not null 在 Postgres 上与原结构等价
+type legacyUser struct {
+	ID           uuid.UUID `gorm:"type:uuid;primary_key;not null"`
+	Username     string    `gorm:"type:varchar(50);not null;uniqueIndex"`
+	Email        string    `gorm:"type:varchar(255);not null;uniqueIndex"`
+	PasswordHash string    `gorm:"type:varchar(255);not null"`
+	CreatedAt    time.Time `gorm:"default:CURRENT_TIMESTAMP"`
+	UpdatedAt    time.Time `gorm:"default:CURRENT_TIMESTAMP"`
+}
+
+func (legacyUser) TableName() string {
+	return "users"
+}
+
+const createRateLimits = `CREATE TABLE rate_limits (
+    key VARCHAR(255) PRIMARY KEY NOT NULL,
+    value BIGINT NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP NOT NULL
+)`
+
+// TestMigrateLegacyAutoMigrate 从旧版 AutoMigrate 的表结构出发：
+// 启动提示的基线版本不跳过缺失的迁移，基线后 migrate up 得到与模型一致的表结构
+func TestMigrateLegacyAutoMigrate(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare []string // AutoMigrate 之后执行的 SQL，模拟部分手工升级的库
+		version uint64
+	}{
+		{name: "automigrate", version: 1},
+		{
+			name:    "with rate_limits",
+			prepare: []string{createRateLimits},
+			version: 2,
+		},
+		{
+			name: "with rate_limits and role",
+			prepare: []string{
+				createRateLimits,
+				"CREATE INDEX idx_rate_limits_expires_at ON rate_limits(expires_at)",
+				"ALTER TABLE users ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'user'",
+			},
+			version: 3,
+		},
+	}
+
+	for _, driver := range databasetest.Drivers {
+		t.Run(driver, func(t *testing.T) {
+			databasetest.SkipUnavailable(t, driver)
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					ctx := context.Background()
+					log := slog.New(slog.DiscardHandler)
+					cfg := &config.Config{Database: config.DatabaseConfig{AutoMigrate: true}}
+
+					db := databasetest.OpenUnmigrated(t, driver)
+					if err := db.AutoMigrate(&legacyUser{}); err != nil {
+						t.Fatal(err)
+					}
+					if driver == database.DriverPostgres {
+						if err := db.Exec("ALTER TABLE users ALTER COLUMN id SET DEFAULT uuid_generate_v4()").Error; err != nil {
+							t.Fatal(err)
+						}
+					}
+					for _, stmt := range tt.prepare {
+						if err := db.Exec(stmt).Error; err != nil {
+							t.Fatalf("%s: %v", stmt, err)
+						}
+					}
+
+					m, err := database.NewMigrator(db, log)
+					if err != nil {
+						t.Fatal(err)
+					}
+					want := fmt.Sprintf("migrate baseline %d`", tt.version)
+					err = database.Migrate(cfg, db, m, log)
+					if err == nil || !strings.Contains(err.Error(), want) {
+						t.Fatalf("Migrate error = %v, want it to suggest %q", err, want)
+					}
+
+					if err := m.Baseline(ctx, tt.version); err != nil {
+						t.Fatal(err)
+					}
+					if err := database.Migrate(cfg, db, m, log); err != nil {
+						t.Fatalf("Migrate after baseline: %v", err)
+					}
+					if err := database.VerifySchema(ctx, db); err != nil {
+						t.Fatalf("schema does not match models:\n%v", err)
+					}
+				})
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"gorm.io/gorm"
+
+	"artisan-coder/internal/config"
+)
+
+// Notify 通过 pg_notify 向 channel 发送通知
+// db 为事务时，通知在提交后才送达，回滚则不发送
+func Notify(db *gorm.DB, channel, payload string) error {
+	return db.Exec("SELECT pg_notify(?, ?)", channel, payload).Error
+}
+
+// Listen 在独立连接上监听 channel，直到 ctx 取消
+// 连接断开后按 database.connectBackoff 退避重连；每次连接建立后调用 onConnect，
+// 断线期间的通知已经丢失，调用方应在其中丢弃依赖通知维护的状态
+func Listen(ctx context.Context, cfg config.DatabaseConfig, channel string, log *slog.Logger, onConnect func(), onNotify func(payload string)) {
+	backoff := cfg.ConnectBackoff
+	if backoff <= 0 {
+		backoff = time.Second
+	}
+
+	wait := backoff
+	for {
+		connected, err := listenOnce(ctx, cfg.DSN(), channel, onConnect, onNotify)
+		if ctx.Err() != nil {
+			return
+		}
+		if connected {
+			wait = backoff
+		}
+		log.Warn("Notification listener disconnected, reconnecting",
+			"channel", channel, "error", err, "backoff", wait.String())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+		wait = min(wait*2, maxConnectBackoff)
+	}
+}
+
+// listenOnce 建立连接并处理通知直到出错，connected 表示是否已开始监听
+func listenOnce(ctx context.Context, dsn, channel string, onConnect func(), onNotify func(payload string)) (connected bool, err error) {
+	conn, err := pgx.Connect(ctx, dsn)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+		return false, fmt.Errorf("listen %s: %w", channel, err)
+	}
+	onConnect()
+
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return true, err
+		}
+		onNotify(n.Payload)
+	}
+}
@@ -0,0 +1,64 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+
+	"artisan-coder/internal/config"
+)
+
+const (
+	// maxConnectBackoff 启动重试的最长等待间隔
+	maxConnectBackoff = 10 * time.Second
+	// pingTimeout 单次连接探测的超时时间
+	pingTimeout = 5 * time.Second
+)
+
+// openPool 按 DSN 创建连接池并应用池参数和语句超时
+// 此时尚未建立连接
+func openPool(dsn string, cfg config.DatabaseConfig) (*sql.DB, error) {
+	connConfig, err := pgx.ParseConfig(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid database DSN: %w", err)
+	}
+
+	// 服务端语句超时，超时的查询由 Postgres 取消
+	if cfg.StatementTimeout > 0 {
+		connConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(cfg.StatementTimeout.Milliseconds(), 10)
+	}
+
+	sqlDB := stdlib.OpenDB(*connConfig)
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+	return sqlDB, nil
+}
+
+// waitForDB 探测数据库直到可用，按指数退避重试
+// docker-compose 等环境中应用常先于 Postgres 就绪
+func waitForDB(sqlDB *sql.DB, cfg config.DatabaseConfig, log *slog.Logger) error {
+	backoff := cfg.ConnectBackoff
+	for attempt := 0; ; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+		err := sqlDB.PingContext(ctx)
+		cancel()
+		if err == nil {
+			return nil
+		}
+		if attempt >= cfg.ConnectRetries {
+			return fmt.Errorf("failed to connect database after %d attempts: %w", attempt+1, err)
+		}
+
+		log.Warn("Database not ready, retrying", "attempt", attempt+1, "retryIn", backoff.String(), "error", err)
+		time.Sleep(backoff)
+		backoff = min(backoff*2, maxConnectBackoff)
+	}
+}
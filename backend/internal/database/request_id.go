@@ -0,0 +1,66 @@
+package database
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"artisan-coder/pkg/requestid"
+)
+
+// requestIDPlugin 为 SQL 语句添加 /* request_id=... */ 注释
+// 慢查询日志和 pg_stat_activity 中即可关联到具体请求
+type requestIDPlugin struct{}
+
+func (requestIDPlugin) Name() string {
+	return "artisan:request_id"
+}
+
+func (requestIDPlugin) Initialize(db *gorm.DB) error {
+	if err := db.Callback().Create().Before("gorm:create").Register("artisan:request_id", commentCallback("INSERT")); err != nil {
+		return err
+	}
+	if err := db.Callback().Query().Before("gorm:query").Register("artisan:request_id", commentCallback("SELECT")); err != nil {
+		return err
+	}
+	if err := db.Callback().Update().Before("gorm:update").Register("artisan:request_id", commentCallback("UPDATE")); err != nil {
+		return err
+	}
+	if err := db.Callback().Delete().Before("gorm:delete").Register("artisan:request_id", commentCallback("DELETE")); err != nil {
+		return err
+	}
+	if err := db.Callback().Row().Before("gorm:row").Register("artisan:request_id", commentCallback("SELECT")); err != nil {
+		return err
+	}
+	return db.Callback().Raw().Before("gorm:raw").Register("artisan:request_id", commentCallback(""))
+}
+
+// commentCallback 返回注入请求 ID 注释的回调
+// 原生 SQL 直接加前缀，其余语句挂到对应子句之前
+func commentCallback(clauseName string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		id := requestid.FromContext(db.Statement.Context)
+		if id == "" {
+			return
+		}
+		comment := "/* request_id=" + id + " */"
+
+		if db.Statement.SQL.Len() > 0 {
+			sql := db.Statement.SQL.String()
+			if strings.HasPrefix(sql, "/* request_id=") {
+				return
+			}
+			db.Statement.SQL.Reset()
+			db.Statement.SQL.WriteString(comment + " " + sql)
+			return
+		}
+
+		if clauseName == "" {
+			return
+		}
+		c := db.Statement.Clauses[clauseName]
+		c.BeforeExpression = clause.Expr{SQL: comment}
+		db.Statement.Clauses[clauseName] = c
+	}
+}
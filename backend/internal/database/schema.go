@@ -0,0 +1,122 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+
+	"artisan-coder/internal/models"
+)
+
+// Models 由迁移管理表结构的 GORM 模型
+func Models() []interface{} {
+	return []interface{}{&models.User{}, &models.RateLimit{}}
+}
+
+// typeAliases 将 SQL 类型名归一为 information_schema.udt_name
+var typeAliases = map[string]string{
+	"character varying":        "varchar",
+	"bigint":                   "int8",
+	"integer":                  "int4",
+	"int":                      "int4",
+	"smallint":                 "int2",
+	"boolean":                  "bool",
+	"timestamp with time zone": "timestamptz",
+	"timestamp":                "timestamp",
+	"double precision":         "float8",
+}
+
+// VerifySchema 对比数据库表结构与 GORM 模型
+// 检查列是否缺失或多余、显式声明的类型和非空约束，所有差异合并为一个错误返回
+// SQLite 的类型只是亲和性提示，仅在 Postgres 下比较类型
+func VerifySchema(ctx context.Context, db *gorm.DB) error {
+	db = db.WithContext(ctx)
+	checkTypes := db.Dialector.Name() == DriverPostgres
+
+	var problems []error
+	for _, model := range Models() {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			return fmt.Errorf("failed to parse model %T: %w", model, err)
+		}
+		table := stmt.Schema.Table
+
+		columnTypes, err := db.Migrator().ColumnTypes(model)
+		if err != nil {
+			return fmt.Errorf("failed to read columns of %s: %w", table, err)
+		}
+		if len(columnTypes) == 0 {
+			problems = append(problems, fmt.Errorf("table %s does not exist", table))
+			continue
+		}
+
+		columns := make(map[string]gorm.ColumnType, len(columnTypes))
+		for _, ct := range columnTypes {
+			columns[ct.Name()] = ct
+		}
+
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName == "" {
+				continue
+			}
+			ct, ok := columns[field.DBName]
+			if !ok {
+				problems = append(problems, fmt.Errorf("%s.%s: column missing", table, field.DBName))
+				continue
+			}
+			delete(columns, field.DBName)
+			problems = append(problems, compareColumn(table, field, ct, checkTypes)...)
+		}
+
+		for name := range columns {
+			problems = append(problems, fmt.Errorf("%s.%s: column not declared in model", table, name))
+		}
+	}
+	return errors.Join(problems...)
+}
+
+func compareColumn(table string, field *schema.Field, ct gorm.ColumnType, checkTypes bool) []error {
+	var problems []error
+
+	if declared := field.TagSettings["TYPE"]; checkTypes && declared != "" {
+		want, wantLength := splitType(declared)
+		got := strings.ToLower(ct.DatabaseTypeName())
+		if want != got {
+			problems = append(problems, fmt.Errorf("%s.%s: type %s, model declares %s", table, field.DBName, got, want))
+		} else if wantLength > 0 {
+			if length, ok := ct.Length(); ok && length != wantLength {
+				problems = append(problems, fmt.Errorf("%s.%s: length %d, model declares %d", table, field.DBName, length, wantLength))
+			}
+		}
+	}
+
+	if nullable, ok := ct.Nullable(); ok {
+		notNull := field.NotNull || field.PrimaryKey
+		if notNull && nullable {
+			problems = append(problems, fmt.Errorf("%s.%s: column is nullable, model declares not null", table, field.DBName))
+		} else if !notNull && !nullable {
+			problems = append(problems, fmt.Errorf("%s.%s: column is not null, model allows null", table, field.DBName))
+		}
+	}
+
+	return problems
+}
+
+// splitType 拆分 varchar(50) 这类声明为归一化类型名和长度
+func splitType(declared string) (string, int64) {
+	declared = strings.ToLower(strings.TrimSpace(declared))
+
+	var length int64
+	if i := strings.Index(declared, "("); i >= 0 {
+		fmt.Sscanf(declared[i:], "(%d)", &length)
+		declared = strings.TrimSpace(declared[:i])
+	}
+	if alias, ok := typeAliases[declared]; ok {
+		declared = alias
+	}
+	return declared, length
+}
@@ -0,0 +1,31 @@
+package database_test
+
+import (
+	"context"
+	"testing"
+
+	"artisan-coder/internal/database"
+	"artisan-coder/internal/database/databasetest"
+)
+
+func TestVerifySchema(t *testing.T) {
+	for _, driver := range databasetest.Drivers {
+		t.Run(driver, func(t *testing.T) {
+			databasetest.SkipUnavailable(t, driver)
+			db := databasetest.Open(t, driver)
+			if err := database.VerifySchema(context.Background(), db); err != nil {
+				t.Fatalf("schema does not match models:\n%v", err)
+			}
+		})
+	}
+}
+
+func TestVerifySchemaReportsDrift(t *testing.T) {
+	db := databasetest.Open(t, database.DriverSQLite)
+	if err := db.Exec("ALTER TABLE users DROP COLUMN last_login_at").Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := database.VerifySchema(context.Background(), db); err == nil {
+		t.Fatal("VerifySchema returned nil for a missing column")
+	}
+}
@@ -0,0 +1,49 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+
+	"artisan-coder/internal/config"
+)
+
+// sqliteMemory 内存数据库路径
+const sqliteMemory = ":memory:"
+
+// openSQLite 打开 SQLite 数据库，用于本地开发和测试
+// 纯 Go 实现，无需 CGO；不支持只读副本、语句超时和 postgres 限流存储
+func openSQLite(cfg config.DatabaseConfig, gormConfig *gorm.Config) (*gorm.DB, error) {
+	pragmas := []string{"foreign_keys(1)", "busy_timeout(5000)"}
+	memory := cfg.Path == sqliteMemory
+	if !memory {
+		// WAL 模式允许读写并发
+		pragmas = append(pragmas, "journal_mode(WAL)")
+	}
+	dsn := cfg.Path + "?_pragma=" + strings.Join(pragmas, "&_pragma=")
+
+	db, err := gorm.Open(translator{sqlite.Open(dsn)}, gormConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite database: %w", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	if memory {
+		// 每个连接都是独立的内存库，只保留一个且不回收
+		sqlDB.SetMaxOpenConns(1)
+		sqlDB.SetMaxIdleConns(1)
+		sqlDB.SetConnMaxLifetime(0)
+		sqlDB.SetConnMaxIdleTime(0)
+	} else {
+		sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+		sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+		sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+		sqlDB.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+	}
+	return db, nil
+}
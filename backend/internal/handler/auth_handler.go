@@ -11,6 +11,7 @@ import (
 	"artisan-coder/internal/models"
 	"artisan-coder/internal/repository"
 	"artisan-coder/internal/service"
+	"artisan-coder/pkg/i18n"
 	"artisan-coder/pkg/response"
 )
 
@@ -49,28 +50,35 @@ type UserResponse struct {
 	Email     string    `json:"email"`
 	CreatedAt time.Time `json:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt"`
+
+	LastLoginAt *time.Time `json:"lastLoginAt,omitempty"`
 }
 
 // Register 用户注册
 func (h *AuthHandler) Register(c *gin.Context) {
 	var req RegisterRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, err.Error())
+		response.ValidationError(c, err)
 		return
 	}
 
 	// 验证密码一致性
 	if req.Password != req.ConfirmPassword {
-		response.BadRequest(c, "Passwords do not match")
+		response.BadRequest(c, i18n.KeyPasswordsMismatch)
 		return
 	}
 
 	// 调用服务层
 	user, accessToken, refreshToken, err := h.authService.Register(c.Request.Context(), req.Username, req.Email, req.Password)
 	if err != nil {
-		if errors.Is(err, repository.ErrUserAlreadyExists) {
-			response.Conflict(c, "User with this email already exists")
-		} else {
+		switch {
+		case errors.Is(err, repository.ErrUsernameTaken):
+			response.Conflict(c, i18n.KeyUsernameTaken)
+		case errors.Is(err, repository.ErrEmailTaken):
+			response.Conflict(c, i18n.KeyUserEmailExists)
+		case errors.Is(err, repository.ErrUserAlreadyExists):
+			response.Conflict(c, "")
+		default:
 			response.InternalError(c)
 		}
 		return
@@ -87,14 +95,21 @@ func (h *AuthHandler) Register(c *gin.Context) {
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.BadRequest(c, err.Error())
+		response.ValidationError(c, err)
 		return
 	}
 
 	// 调用服务层
 	user, accessToken, refreshToken, err := h.authService.Login(c.Request.Context(), req.Email, req.Password)
 	if err != nil {
-		response.Unauthorized(c, "Invalid email or password")
+		switch {
+		case errors.Is(err, service.ErrInvalidCredentials):
+			response.Unauthorized(c, i18n.KeyInvalidCredentials)
+		case errors.Is(err, service.ErrUserDisabled):
+			response.Forbidden(c, i18n.KeyAccountDisabled)
+		default:
+			response.InternalError(c)
+		}
 		return
 	}
 
@@ -116,7 +131,7 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 func (h *AuthHandler) RefreshToken(c *gin.Context) {
 	refreshToken := c.GetHeader("Authorization")
 	if refreshToken == "" {
-		response.Unauthorized(c, "Missing refresh token")
+		response.Unauthorized(c, i18n.KeyMissingRefreshToken)
 		return
 	}
 
@@ -128,7 +143,11 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 	// 调用服务层
 	user, accessToken, newRefreshToken, err := h.authService.RefreshToken(c.Request.Context(), refreshToken)
 	if err != nil {
-		response.Unauthorized(c, "Invalid or expired refresh token")
+		if errors.Is(err, service.ErrUserDisabled) {
+			response.Forbidden(c, i18n.KeyAccountDisabled)
+			return
+		}
+		response.Unauthorized(c, i18n.KeyInvalidRefreshToken)
 		return
 	}
 
@@ -143,18 +162,23 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 func (h *AuthHandler) GetCurrentUser(c *gin.Context) {
 	userIDStr, exists := c.Get("user_id")
 	if !exists {
-		response.Unauthorized(c, "User not authenticated")
+		response.Unauthorized(c, i18n.KeyNotAuthenticated)
 		return
 	}
 
 	userID, err := uuid.Parse(userIDStr.(string))
 	if err != nil {
-		response.BadRequest(c, "Invalid user ID")
+		response.BadRequest(c, i18n.KeyInvalidUserID)
 		return
 	}
 
 	user, err := h.authService.GetUserByID(c.Request.Context(), userID)
 	if err != nil {
+		// 用户状态有短暂缓存，期间被删除的用户在这里兜底
+		if errors.Is(err, repository.ErrUserNotFound) {
+			response.Unauthorized(c, i18n.KeyInvalidOrExpiredToken)
+			return
+		}
 		response.InternalError(c)
 		return
 	}
@@ -169,6 +193,8 @@ func toUserResponse(user *models.User) *UserResponse {
 		Email:     user.Email,
 		CreatedAt: user.CreatedAt,
 		UpdatedAt: user.UpdatedAt,
+
+		LastLoginAt: user.LastLoginAt,
 	}
 }
 
@@ -176,5 +202,6 @@ func toUserResponse(user *models.User) *UserResponse {
 func Module() fx.Option {
 	return fx.Provide(
 		NewAuthHandler,
+		NewHealthHandler,
 	)
 }
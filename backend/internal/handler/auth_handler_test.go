@@ -0,0 +1,136 @@
+package handler_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"artisan-coder/internal/handler"
+	"artisan-coder/internal/testkit"
+)
+
+// backends 每个用例分别在 SQLite 和内存仓储上运行
+var backends = []struct {
+	name string
+	opts []testkit.Option
+}{
+	{name: "sqlite"},
+	{name: "memory", opts: []testkit.Option{testkit.WithMemoryRepositories()}},
+}
+
+func eachBackend(t *testing.T, fn func(t *testing.T, kit *testkit.Kit)) {
+	for _, b := range backends {
+		t.Run(b.name, func(t *testing.T) {
+			fn(t, testkit.New(t, b.opts...))
+		})
+	}
+}
+
+func TestRegister(t *testing.T) {
+	eachBackend(t, func(t *testing.T, kit *testkit.Kit) {
+		auth := kit.Register(t, "alice", "alice@example.com", "password123")
+		if auth.User.Username != "alice" || auth.User.Email != "alice@example.com" {
+			t.Errorf("user = %+v", auth.User)
+		}
+		if auth.Token == "" || auth.RefreshToken == "" {
+			t.Error("missing tokens")
+		}
+
+		kit.Do(t, http.MethodPost, "/api/auth/register", handler.RegisterRequest{
+			Username:        "alice2",
+			Email:           "alice@example.com",
+			Password:        "password123",
+			ConfirmPassword: "password123",
+		}).RequireStatus(t, http.StatusConflict)
+
+		kit.Do(t, http.MethodPost, "/api/auth/register", handler.RegisterRequest{
+			Username:        "bob",
+			Email:           "bob@example.com",
+			Password:        "password123",
+			ConfirmPassword: "password456",
+		}).RequireStatus(t, http.StatusBadRequest)
+	})
+}
+
+func TestLogin(t *testing.T) {
+	eachBackend(t, func(t *testing.T, kit *testkit.Kit) {
+		kit.Register(t, "alice", "alice@example.com", "password123")
+
+		auth := kit.Login(t, "alice@example.com", "password123")
+		if auth.User.LastLoginAt == nil || !auth.User.LastLoginAt.Equal(kit.Clock.Now()) {
+			t.Errorf("LastLoginAt = %v, want %v", auth.User.LastLoginAt, kit.Clock.Now())
+		}
+
+		for _, req := range []handler.LoginRequest{
+			{Email: "alice@example.com", Password: "wrong-password"},
+			{Email: "nobody@example.com", Password: "password123"},
+		} {
+			kit.Do(t, http.MethodPost, "/api/auth/login", req).RequireStatus(t, http.StatusUnauthorized)
+		}
+	})
+}
+
+func TestRefresh(t *testing.T) {
+	eachBackend(t, func(t *testing.T, kit *testkit.Kit) {
+		auth := kit.Register(t, "alice", "alice@example.com", "password123")
+
+		// 访问令牌过期，刷新令牌仍有效
+		kit.Clock.Advance(2 * time.Hour)
+		kit.Do(t, http.MethodGet, "/api/auth/me", nil, testkit.WithToken(auth.Token)).
+			RequireStatus(t, http.StatusUnauthorized)
+
+		var refreshed handler.AuthResponse
+		kit.Do(t, http.MethodPost, "/api/auth/refresh", nil, testkit.WithToken(auth.RefreshToken)).
+			RequireStatus(t, http.StatusOK).Data(t, &refreshed)
+		kit.Do(t, http.MethodGet, "/api/auth/me", nil, testkit.WithToken(refreshed.Token)).
+			RequireStatus(t, http.StatusOK)
+
+		kit.Do(t, http.MethodPost, "/api/auth/refresh", nil).RequireStatus(t, http.StatusUnauthorized)
+		kit.Do(t, http.MethodPost, "/api/auth/refresh", nil, testkit.WithToken("not-a-token")).
+			RequireStatus(t, http.StatusUnauthorized)
+
+		// 刷新令牌过期
+		kit.Clock.Advance(8 * 24 * time.Hour)
+		kit.Do(t, http.MethodPost, "/api/auth/refresh", nil, testkit.WithToken(refreshed.RefreshToken)).
+			RequireStatus(t, http.StatusUnauthorized)
+	})
+}
+
+func TestMe(t *testing.T) {
+	eachBackend(t, func(t *testing.T, kit *testkit.Kit) {
+		auth := kit.Register(t, "alice", "alice@example.com", "password123")
+
+		kit.Do(t, http.MethodGet, "/api/auth/me", nil).RequireStatus(t, http.StatusUnauthorized)
+
+		var me handler.UserResponse
+		kit.Do(t, http.MethodGet, "/api/auth/me", nil, testkit.WithToken(auth.Token)).
+			RequireStatus(t, http.StatusOK).Data(t, &me)
+		if me.ID != auth.User.ID || me.Email != "alice@example.com" {
+			t.Errorf("me = %+v", me)
+		}
+
+		ctx := context.Background()
+		user, err := kit.UserRepository.FindByEmail(ctx, "alice@example.com")
+		if err != nil {
+			t.Fatal(err)
+		}
+		now := kit.Clock.Now()
+		user.DisabledAt = &now
+		if err := kit.UserRepository.Update(ctx, user); err != nil {
+			t.Fatal(err)
+		}
+		kit.Do(t, http.MethodGet, "/api/auth/me", nil, testkit.WithToken(auth.Token)).
+			RequireStatus(t, http.StatusForbidden)
+		kit.Do(t, http.MethodPost, "/api/auth/login", handler.LoginRequest{
+			Email:    "alice@example.com",
+			Password: "password123",
+		}).RequireStatus(t, http.StatusForbidden)
+
+		if err := kit.UserRepository.Delete(ctx, user.ID); err != nil {
+			t.Fatal(err)
+		}
+		kit.Do(t, http.MethodGet, "/api/auth/me", nil, testkit.WithToken(auth.Token)).
+			RequireStatus(t, http.StatusUnauthorized)
+	})
+}
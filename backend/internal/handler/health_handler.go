@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"artisan-coder/internal/health"
+)
+
+type HealthHandler struct {
+	health *health.Health
+}
+
+func NewHealthHandler(h *health.Health) *HealthHandler {
+	return &HealthHandler{
+		health: h,
+	}
+}
+
+// Liveness 存活探针
+func (h *HealthHandler) Liveness(c *gin.Context) {
+	writeReport(c, h.health.Liveness(c.Request.Context()))
+}
+
+// Readiness 就绪探针
+func (h *HealthHandler) Readiness(c *gin.Context) {
+	writeReport(c, h.health.Readiness(c.Request.Context()))
+}
+
+// writeReport 探针直接输出检查报告，不使用统一响应信封
+func writeReport(c *gin.Context, report health.Report) {
+	status := http.StatusOK
+	if !report.OK() {
+		status = http.StatusServiceUnavailable
+	}
+	c.Header("Cache-Control", "no-store")
+	c.JSON(status, report)
+}
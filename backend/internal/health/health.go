@@ -0,0 +1,144 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/fx"
+)
+
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+// checkTimeout 单个检查的超时时间
+const checkTimeout = 3 * time.Second
+
+// ErrShuttingDown 服务正在关闭，就绪检查失败以便负载均衡摘除流量
+var ErrShuttingDown = errors.New("server is shutting down")
+
+// Checker 就绪检查项
+type Checker interface {
+	Name() string
+	Check(ctx context.Context) error
+}
+
+// CheckResult 单个检查项结果
+type CheckResult struct {
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latencyMs"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Report 检查汇总
+type Report struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+// OK 所有检查是否通过
+func (r Report) OK() bool {
+	return r.Status == StatusOK
+}
+
+// Health 管理存活与就绪状态
+type Health struct {
+	checkers     []Checker
+	shuttingDown atomic.Bool
+}
+
+// In 通过 value group 收集各模块贡献的检查项
+type In struct {
+	fx.In
+	Checkers []Checker `group:"readiness_checkers"`
+}
+
+// New 创建 Health
+func New(in In) *Health {
+	return &Health{checkers: in.Checkers}
+}
+
+// AsChecker 将构造函数注解为就绪检查项，供其他模块贡献
+//
+//	fx.Provide(health.AsChecker(NewRedisChecker))
+func AsChecker(f interface{}) interface{} {
+	return fx.Annotate(
+		f,
+		fx.As(new(Checker)),
+		fx.ResultTags(`group:"readiness_checkers"`),
+	)
+}
+
+// MarkShuttingDown 标记服务关闭中，之后就绪检查始终失败
+func (h *Health) MarkShuttingDown() {
+	h.shuttingDown.Store(true)
+}
+
+// Liveness 存活检查，仅反映进程是否正常响应
+func (h *Health) Liveness(ctx context.Context) Report {
+	return Report{
+		Status: StatusOK,
+		Checks: map[string]CheckResult{},
+	}
+}
+
+// Readiness 并发执行所有就绪检查
+func (h *Health) Readiness(ctx context.Context) Report {
+	report := Report{
+		Status: StatusOK,
+		Checks: make(map[string]CheckResult, len(h.checkers)+1),
+	}
+
+	if h.shuttingDown.Load() {
+		report.Status = StatusFail
+		report.Checks["shutdown"] = CheckResult{Status: StatusFail, Error: ErrShuttingDown.Error()}
+	}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, checker := range h.checkers {
+		wg.Add(1)
+		go func(checker Checker) {
+			defer wg.Done()
+			result := run(ctx, checker)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[checker.Name()] = result
+			if result.Status != StatusOK {
+				report.Status = StatusFail
+			}
+		}(checker)
+	}
+	wg.Wait()
+
+	return report
+}
+
+func run(ctx context.Context, checker Checker) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := checker.Check(ctx)
+	result := CheckResult{
+		Status:    StatusOK,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Status = StatusFail
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// Module 返回健康检查模块的 FX 选项
+func Module() fx.Option {
+	return fx.Provide(New)
+}
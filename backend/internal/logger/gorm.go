@@ -0,0 +1,85 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// GormLogger 将 GORM 日志输出到 slog
+// 超过慢查询阈值的语句以 warn 级别记录
+type GormLogger struct {
+	logger        *slog.Logger
+	level         gormlogger.LogLevel
+	slowThreshold time.Duration
+	expected      func(error) bool
+}
+
+// NewGormLogger 创建 GORM 日志适配器
+// expected 判断业务上可预期的错误（如唯一约束冲突），以 warn 级别记录；为 nil 时所有错误记为 error
+func NewGormLogger(logger *slog.Logger, slowThreshold time.Duration, expected func(error) bool) *GormLogger {
+	return &GormLogger{
+		logger:        logger.With("component", "gorm"),
+		level:         gormlogger.Info,
+		slowThreshold: slowThreshold,
+		expected:      expected,
+	}
+}
+
+func (l *GormLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	clone := *l
+	clone.level = level
+	return &clone
+}
+
+func (l *GormLogger) Info(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= gormlogger.Info {
+		l.logger.InfoContext(ctx, fmt.Sprintf(msg, args...))
+	}
+}
+
+func (l *GormLogger) Warn(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= gormlogger.Warn {
+		l.logger.WarnContext(ctx, fmt.Sprintf(msg, args...))
+	}
+}
+
+func (l *GormLogger) Error(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= gormlogger.Error {
+		l.logger.ErrorContext(ctx, fmt.Sprintf(msg, args...))
+	}
+}
+
+// Trace 记录 SQL 执行情况
+// 错误（记录不存在除外）记为 error，可预期的错误和慢查询记为 warn，其余为 debug
+func (l *GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	if l.level <= gormlogger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	attrs := func() []any {
+		sql, rows := fc()
+		return []any{
+			slog.String("sql", sql),
+			slog.Int64("rows", rows),
+			slog.Duration("elapsed", elapsed),
+		}
+	}
+
+	switch {
+	case err != nil && l.expected != nil && l.expected(err) && l.level >= gormlogger.Warn:
+		l.logger.WarnContext(ctx, "sql error", append(attrs(), slog.String("error", err.Error()))...)
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound) && l.level >= gormlogger.Error:
+		l.logger.ErrorContext(ctx, "sql error", append(attrs(), slog.String("error", err.Error()))...)
+	case l.slowThreshold > 0 && elapsed > l.slowThreshold && l.level >= gormlogger.Warn:
+		l.logger.WarnContext(ctx, "slow sql", append(attrs(), slog.Duration("threshold", l.slowThreshold))...)
+	case l.level >= gormlogger.Info && l.logger.Enabled(ctx, slog.LevelDebug):
+		l.logger.DebugContext(ctx, "sql", attrs()...)
+	}
+}
@@ -0,0 +1,119 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
+
+	"artisan-coder/internal/config"
+	"artisan-coder/pkg/requestid"
+)
+
+// Module 返回日志模块的 FX 选项
+// 同时将 fx 自身的事件日志接入 slog
+func Module() fx.Option {
+	return fx.Options(
+		fx.Provide(
+			NewLevel,
+			NewLogger,
+		),
+		fx.Invoke(WatchLevel),
+		fx.WithLogger(NewFxLogger),
+	)
+}
+
+// WatchLevel 配置文件中的日志级别变化时同步更新
+func WatchLevel(w *config.Watcher, level *slog.LevelVar, logger *slog.Logger) {
+	w.Subscribe(func(old, new *config.Config) {
+		if old.Log.Level == new.Log.Level {
+			return
+		}
+		l, err := ParseLevel(new.Log.Level)
+		if err != nil {
+			logger.Error("Invalid log level in reloaded config", "error", err)
+			return
+		}
+		level.Set(l)
+	})
+}
+
+// NewFxLogger 创建 fx 事件日志
+// 常规事件记为 debug，错误仍为 error
+func NewFxLogger(logger *slog.Logger) fxevent.Logger {
+	l := &fxevent.SlogLogger{Logger: logger.With("component", "fx")}
+	l.UseLogLevel(slog.LevelDebug)
+	return l
+}
+
+// NewLevel 根据配置创建可动态调整的日志级别
+func NewLevel(cfg *config.Config) (*slog.LevelVar, error) {
+	level, err := ParseLevel(cfg.Log.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	var lv slog.LevelVar
+	lv.Set(level)
+	return &lv, nil
+}
+
+// NewLogger 创建 slog Logger 并设置为全局默认
+func NewLogger(cfg *config.Config, level *slog.LevelVar) (*slog.Logger, error) {
+	handler, err := newHandler(os.Stdout, cfg.Log.Format, level)
+	if err != nil {
+		return nil, err
+	}
+
+	logger := slog.New(contextHandler{Handler: handler})
+	slog.SetDefault(logger)
+	return logger, nil
+}
+
+// ParseLevel 解析日志级别：debug, info, warn, error
+func ParseLevel(s string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(strings.TrimSpace(s))); err != nil {
+		return 0, fmt.Errorf("invalid log level %q: %w", s, err)
+	}
+	return level, nil
+}
+
+// newHandler 按格式创建 handler：json, text
+func newHandler(w io.Writer, format string, level slog.Leveler) (slog.Handler, error) {
+	opts := &slog.HandlerOptions{Level: level}
+
+	switch strings.ToLower(format) {
+	case "", "json":
+		return slog.NewJSONHandler(w, opts), nil
+	case "text":
+		return slog.NewTextHandler(w, opts), nil
+	default:
+		return nil, fmt.Errorf("invalid log format %q", format)
+	}
+}
+
+// contextHandler 从 context 中提取请求 ID 附加到每条日志
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := requestid.FromContext(ctx); id != "" {
+		r.AddAttrs(slog.String("request_id", id))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{Handler: h.Handler.WithGroup(name)}
+}
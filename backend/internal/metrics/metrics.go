@@ -0,0 +1,113 @@
+package metrics
+
+import (
+	"errors"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+const namespace = "artisan"
+
+// Metrics 应用指标
+type Metrics struct {
+	HTTPRequests *prometheus.CounterVec
+	HTTPDuration *prometheus.HistogramVec
+
+	Registrations *prometheus.CounterVec
+	Logins        *prometheus.CounterVec
+	Refreshes     *prometheus.CounterVec
+
+	CacheRequests *prometheus.CounterVec
+}
+
+// New 创建并注册应用指标
+func New(reg prometheus.Registerer) *Metrics {
+	m := &Metrics{
+		HTTPRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "Total number of HTTP requests by route template, method and status.",
+		}, []string{"method", "route", "status"}),
+		HTTPDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "HTTP request latency by route template, method and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+
+		Registrations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "auth",
+			Name:      "registrations_total",
+			Help:      "Total number of user registrations by result.",
+		}, []string{"result"}),
+		Logins: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "auth",
+			Name:      "logins_total",
+			Help:      "Total number of login attempts by result.",
+		}, []string{"result"}),
+		Refreshes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "auth",
+			Name:      "token_refreshes_total",
+			Help:      "Total number of token refreshes by result.",
+		}, []string{"result"}),
+		CacheRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "cache",
+			Name:      "requests_total",
+			Help:      "Total number of cache lookups by cache and result.",
+		}, []string{"cache", "result"}),
+	}
+
+	reg.MustRegister(
+		m.HTTPRequests,
+		m.HTTPDuration,
+		m.Registrations,
+		m.Logins,
+		m.Refreshes,
+		m.CacheRequests,
+	)
+	return m
+}
+
+// NewRegistry 创建包含 Go 运行时和进程指标的注册表
+func NewRegistry() *prometheus.Registry {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(
+		collectors.NewGoCollector(collectors.WithGoCollectorRuntimeMetrics(collectors.MetricsAll)),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return reg
+}
+
+// 业务指标结果标签
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+	ResultError   = "error"
+)
+
+// 缓存查询结果标签
+const (
+	CacheHit  = "hit"
+	CacheMiss = "miss"
+)
+
+// Result 将错误归类为结果标签
+// 预期内的业务错误（如密码错误）记为 failure，其余错误记为 error
+func Result(err error, expected ...error) string {
+	if err == nil {
+		return ResultSuccess
+	}
+	for _, target := range expected {
+		if errors.Is(err, target) {
+			return ResultFailure
+		}
+	}
+	return ResultError
+}
@@ -0,0 +1,85 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.uber.org/fx"
+	"gorm.io/gorm"
+
+	"artisan-coder/internal/config"
+)
+
+// Module 返回指标模块的 FX 选项
+func Module() fx.Option {
+	return fx.Options(
+		fx.Provide(
+			NewRegistry,
+			func(reg *prometheus.Registry) prometheus.Registerer { return reg },
+			func(reg *prometheus.Registry) prometheus.Gatherer { return reg },
+			New,
+			NewHandler,
+		),
+		fx.Invoke(
+			RegisterDBStats,
+			RegisterServer,
+		),
+	)
+}
+
+// Handler /metrics 处理器
+type Handler http.Handler
+
+// NewHandler 创建 /metrics 处理器
+func NewHandler(gatherer prometheus.Gatherer) Handler {
+	return promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{})
+}
+
+// RegisterDBStats 导出 database/sql 连接池状态
+func RegisterDBStats(reg prometheus.Registerer, db *gorm.DB, cfg *config.Config) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return reg.Register(collectors.NewDBStatsCollector(sqlDB, cfg.Database.DBName))
+}
+
+// RegisterServer 配置了独立端口时，在单独的 HTTP 服务器上暴露指标
+func RegisterServer(lc fx.Lifecycle, cfg *config.Config, handler Handler, log *slog.Logger) {
+	if !cfg.Metrics.Enabled || cfg.Metrics.Port == "" {
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(cfg.Metrics.Path, handler)
+	server := &http.Server{
+		Addr:    ":" + cfg.Metrics.Port,
+		Handler: mux,
+	}
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			ln, err := net.Listen("tcp", server.Addr)
+			if err != nil {
+				return err
+			}
+			log.Info("Starting metrics server", "port", cfg.Metrics.Port, "path", cfg.Metrics.Path)
+
+			go func() {
+				if err := server.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+					log.Error("Metrics server failed", "error", err)
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			return server.Shutdown(ctx)
+		},
+	})
+}
@@ -1,9 +1,15 @@
 package middleware
 
 import (
+	"context"
+	"errors"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"artisan-coder/internal/service"
+	"artisan-coder/pkg/i18n"
 	"artisan-coder/pkg/jwt"
 	"artisan-coder/pkg/response"
 )
@@ -12,11 +18,17 @@ const (
 	userIDKey = "user_id"
 )
 
-func Auth(jwtManager *jwt.Manager) gin.HandlerFunc {
+// UserChecker 校验令牌所属用户当前是否可用，见 service.UserStatusChecker
+type UserChecker interface {
+	Check(ctx context.Context, userID uuid.UUID) error
+}
+
+// Auth 校验访问令牌，并拒绝已禁用或已删除用户的令牌
+func Auth(jwtManager *jwt.Manager, users UserChecker) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			response.Unauthorized(c, "Missing authorization token")
+			response.Unauthorized(c, i18n.KeyMissingAuthToken)
 			c.Abort()
 			return
 		}
@@ -24,7 +36,7 @@ func Auth(jwtManager *jwt.Manager) gin.HandlerFunc {
 		// 解析 Bearer token
 		parts := strings.SplitN(authHeader, " ", 2)
 		if len(parts) != 2 || parts[0] != "Bearer" {
-			response.Unauthorized(c, "Invalid authorization format")
+			response.Unauthorized(c, i18n.KeyInvalidAuthFormat)
 			c.Abort()
 			return
 		}
@@ -32,7 +44,20 @@ func Auth(jwtManager *jwt.Manager) gin.HandlerFunc {
 		tokenString := parts[1]
 		claims, err := jwtManager.ValidateToken(tokenString)
 		if err != nil {
-			response.Unauthorized(c, "Invalid or expired token")
+			response.Unauthorized(c, i18n.KeyInvalidOrExpiredToken)
+			c.Abort()
+			return
+		}
+
+		if err := users.Check(c.Request.Context(), claims.UserID); err != nil {
+			switch {
+			case errors.Is(err, service.ErrUserDisabled):
+				response.Forbidden(c, i18n.KeyAccountDisabled)
+			case errors.Is(err, service.ErrUserDeleted):
+				response.Unauthorized(c, i18n.KeyInvalidOrExpiredToken)
+			default:
+				response.InternalError(c)
+			}
 			c.Abort()
 			return
 		}
@@ -1,36 +1,245 @@
 package middleware
 
 import (
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
 	"github.com/gin-gonic/gin"
+
+	"artisan-coder/internal/config"
 )
 
-func CORS(allowedOrigins []string) gin.HandlerFunc {
+// corsPolicy 预处理后的 CORS 配置
+type corsPolicy struct {
+	allowAll         bool                // 配置了 "*"
+	origins          map[string]struct{} // 精确匹配的源
+	patterns         []originPattern     // 通配子域名，如 https://*.example.com
+	methods          map[string]struct{}
+	allowAllMethods  bool // 配置了 "*"
+	methodsValue     string
+	headers          map[string]struct{}
+	allowAllHeaders  bool
+	headersValue     string
+	exposeValue      string
+	allowCredentials bool
+	maxAge           string
+}
+
+// originPattern 通配子域名匹配规则
+type originPattern struct {
+	prefix string // scheme://
+	suffix string // .example.com[:port]
+}
+
+func (p originPattern) match(origin string) bool {
+	if !strings.HasPrefix(origin, p.prefix) || !strings.HasSuffix(origin, p.suffix) {
+		return false
+	}
+	// 通配部分至少一个字符
+	return len(origin) > len(p.prefix)+len(p.suffix)
+}
+
+// CORS 跨域中间件
+// 按配置校验源、预检方法和请求头；通配源 "*" 不会携带凭证
+func CORS(cfg config.CORSConfig) gin.HandlerFunc {
+	return newCORSPolicy(cfg).handle
+}
+
+// ReloadableCORS 跨域中间件，配置热更新时重建策略
+func ReloadableCORS(w *config.Watcher) gin.HandlerFunc {
+	var current atomic.Pointer[corsPolicy]
+	current.Store(newCORSPolicy(w.Current().CORS))
+
+	w.Subscribe(func(old, new *config.Config) {
+		if !reflect.DeepEqual(old.CORS, new.CORS) {
+			current.Store(newCORSPolicy(new.CORS))
+		}
+	})
+
 	return func(c *gin.Context) {
-		origin := c.Request.Header.Get("Origin")
-
-		// 检查是否允许该源
-		allowed := false
-		for _, allowedOrigin := range allowedOrigins {
-			if allowedOrigin == "*" || allowedOrigin == origin {
-				allowed = true
-				break
+		current.Load().handle(c)
+	}
+}
+
+func (p *corsPolicy) handle(c *gin.Context) {
+	origin := c.Request.Header.Get("Origin")
+	header := c.Writer.Header()
+
+	// 响应随 Origin 变化，缓存需区分
+	header.Add("Vary", "Origin")
+
+	preflight := c.Request.Method == http.MethodOptions &&
+		c.Request.Header.Get("Access-Control-Request-Method") != ""
+	if preflight {
+		header.Add("Vary", "Access-Control-Request-Method")
+		header.Add("Vary", "Access-Control-Request-Headers")
+	}
+
+	// 非跨域请求
+	if origin == "" {
+		c.Next()
+		return
+	}
+
+	allowOrigin, credentials, ok := p.allowOrigin(origin)
+	if !ok {
+		if preflight {
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+		// 普通请求不附加 CORS 头，由浏览器拦截
+		c.Next()
+		return
+	}
+
+	if preflight {
+		method := strings.ToUpper(strings.TrimSpace(c.Request.Header.Get("Access-Control-Request-Method")))
+		if !p.allowMethod(method) ||
+			!p.allowHeaders(c.Request.Header.Get("Access-Control-Request-Headers")) {
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+
+		header.Set("Access-Control-Allow-Origin", allowOrigin)
+		if credentials {
+			header.Set("Access-Control-Allow-Credentials", "true")
+		}
+		if p.allowAllMethods {
+			// 回显请求方法，携带凭证时浏览器不把 "*" 视为通配
+			header.Set("Access-Control-Allow-Methods", method)
+		} else {
+			header.Set("Access-Control-Allow-Methods", p.methodsValue)
+		}
+		if p.allowAllHeaders {
+			// 回显请求头，兼容携带凭证时不支持 "*" 的情况
+			if requested := c.Request.Header.Get("Access-Control-Request-Headers"); requested != "" {
+				header.Set("Access-Control-Allow-Headers", requested)
 			}
+		} else if p.headersValue != "" {
+			header.Set("Access-Control-Allow-Headers", p.headersValue)
 		}
+		if p.maxAge != "" {
+			header.Set("Access-Control-Max-Age", p.maxAge)
+		}
+		c.AbortWithStatus(http.StatusNoContent)
+		return
+	}
 
-		if allowed {
-			c.Writer.Header().Set("Access-Control-Allow-Origin", origin)
-			c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
+	header.Set("Access-Control-Allow-Origin", allowOrigin)
+	if credentials {
+		header.Set("Access-Control-Allow-Credentials", "true")
+	}
+	if p.exposeValue != "" {
+		header.Set("Access-Control-Expose-Headers", p.exposeValue)
+	}
+	c.Next()
+}
+
+func newCORSPolicy(cfg config.CORSConfig) *corsPolicy {
+	p := &corsPolicy{
+		origins:          make(map[string]struct{}),
+		methods:          make(map[string]struct{}),
+		headers:          make(map[string]struct{}),
+		allowCredentials: cfg.AllowCredentials,
+	}
+
+	for _, origin := range cfg.AllowedOrigins {
+		origin = strings.ToLower(strings.TrimSpace(origin))
+		switch {
+		case origin == "":
+		case origin == "*":
+			p.allowAll = true
+		case strings.Contains(origin, "://*."):
+			i := strings.Index(origin, "*")
+			p.patterns = append(p.patterns, originPattern{prefix: origin[:i], suffix: origin[i+1:]})
+		default:
+			p.origins[origin] = struct{}{}
 		}
+	}
 
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Origin, Content-Type, Authorization")
-		c.Writer.Header().Set("Access-Control-Expose-Headers", "Content-Length")
+	methods := make([]string, 0, len(cfg.AllowedMethods))
+	for _, method := range cfg.AllowedMethods {
+		method = strings.ToUpper(strings.TrimSpace(method))
+		switch method {
+		case "":
+			continue
+		case "*":
+			p.allowAllMethods = true
+			continue
+		}
+		if _, dup := p.methods[method]; !dup {
+			p.methods[method] = struct{}{}
+			methods = append(methods, method)
+		}
+	}
+	p.methodsValue = strings.Join(methods, ", ")
 
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
-			return
+	headers := make([]string, 0, len(cfg.AllowedHeaders))
+	for _, h := range cfg.AllowedHeaders {
+		h = strings.TrimSpace(h)
+		switch {
+		case h == "":
+		case h == "*":
+			p.allowAllHeaders = true
+		default:
+			p.headers[strings.ToLower(h)] = struct{}{}
+			headers = append(headers, http.CanonicalHeaderKey(h))
 		}
+	}
+	p.headersValue = strings.Join(headers, ", ")
 
-		c.Next()
+	p.exposeValue = strings.Join(cfg.ExposedHeaders, ", ")
+
+	if cfg.MaxAge > 0 {
+		p.maxAge = strconv.Itoa(int(cfg.MaxAge.Seconds()))
+	}
+
+	return p
+}
+
+// allowOrigin 返回 Allow-Origin 的值及是否允许携带凭证
+// "*" 匹配时返回字面量 "*" 并拒绝凭证，避免任意源读取带 Cookie 的响应
+func (p *corsPolicy) allowOrigin(origin string) (string, bool, bool) {
+	normalized := strings.ToLower(origin)
+
+	if _, ok := p.origins[normalized]; ok {
+		return origin, p.allowCredentials, true
+	}
+	for _, pattern := range p.patterns {
+		if pattern.match(normalized) {
+			return origin, p.allowCredentials, true
+		}
+	}
+	if p.allowAll {
+		return "*", false, true
+	}
+	return "", false, false
+}
+
+func (p *corsPolicy) allowMethod(method string) bool {
+	if p.allowAllMethods {
+		return true
+	}
+	_, ok := p.methods[method]
+	return ok
+}
+
+// allowHeaders 校验预检请求声明的请求头均在白名单内
+func (p *corsPolicy) allowHeaders(requested string) bool {
+	if p.allowAllHeaders {
+		return true
+	}
+	for _, h := range strings.Split(requested, ",") {
+		h = strings.ToLower(strings.TrimSpace(h))
+		if h == "" {
+			continue
+		}
+		if _, ok := p.headers[h]; !ok {
+			return false
+		}
 	}
+	return true
 }
@@ -0,0 +1,217 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"artisan-coder/internal/config"
+	"artisan-coder/internal/middleware"
+)
+
+// corsRequest 经过 CORS 中间件发出请求，headers 为请求头
+func corsRequest(t *testing.T, cfg config.CORSConfig, method string, headers map[string]string) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.CORS(cfg))
+	r.Any("/api/ping", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(method, "/api/ping", nil)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCORSOrigins(t *testing.T) {
+	cfg := config.CORSConfig{
+		AllowedOrigins:   []string{"https://app.example.com", "https://*.example.org", "*"},
+		AllowedMethods:   []string{"GET", "POST"},
+		AllowedHeaders:   []string{"Content-Type"},
+		ExposedHeaders:   []string{"X-Request-ID"},
+		AllowCredentials: true,
+	}
+	tests := []struct {
+		name            string
+		origin          string
+		wantOrigin      string
+		wantCredentials bool
+	}{
+		{name: "exact", origin: "https://app.example.com", wantOrigin: "https://app.example.com", wantCredentials: true},
+		{name: "exact is case-insensitive", origin: "https://APP.example.com", wantOrigin: "https://APP.example.com", wantCredentials: true},
+		{name: "wildcard subdomain", origin: "https://a.b.example.org", wantOrigin: "https://a.b.example.org", wantCredentials: true},
+		// 通配部分不能为空，scheme 须一致
+		{name: "wildcard needs a subdomain", origin: "https://.example.org", wantOrigin: "*"},
+		{name: "wildcard scheme mismatch", origin: "http://a.example.org", wantOrigin: "*"},
+		{name: "any origin refuses credentials", origin: "https://evil.test", wantOrigin: "*"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := corsRequest(t, cfg, http.MethodGet, map[string]string{"Origin": tt.origin})
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d", rec.Code)
+			}
+			if got := rec.Header().Get("Access-Control-Allow-Origin"); got != tt.wantOrigin {
+				t.Errorf("Allow-Origin = %q, want %q", got, tt.wantOrigin)
+			}
+			if got := rec.Header().Get("Access-Control-Allow-Credentials") == "true"; got != tt.wantCredentials {
+				t.Errorf("Allow-Credentials = %t, want %t", got, tt.wantCredentials)
+			}
+			if got := rec.Header().Get("Access-Control-Expose-Headers"); got != "X-Request-ID" {
+				t.Errorf("Expose-Headers = %q", got)
+			}
+		})
+	}
+}
+
+func TestCORSDisallowedOrigin(t *testing.T) {
+	cfg := config.CORSConfig{
+		AllowedOrigins: []string{"https://app.example.com", "https://*.example.org"},
+		AllowedMethods: []string{"GET"},
+	}
+	for _, origin := range []string{"https://evil.test", "https://example.org", "https://app.example.com.evil.test"} {
+		rec := corsRequest(t, cfg, http.MethodGet, map[string]string{"Origin": origin})
+		if rec.Code != http.StatusOK || rec.Header().Get("Access-Control-Allow-Origin") != "" {
+			t.Errorf("%s: status = %d, Allow-Origin = %q; want the request without CORS headers",
+				origin, rec.Code, rec.Header().Get("Access-Control-Allow-Origin"))
+		}
+
+		rec = corsRequest(t, cfg, http.MethodOptions, map[string]string{
+			"Origin":                        origin,
+			"Access-Control-Request-Method": "GET",
+		})
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("%s: preflight status = %d, want 403", origin, rec.Code)
+		}
+	}
+}
+
+func TestCORSPreflight(t *testing.T) {
+	base := config.CORSConfig{
+		AllowedOrigins:   []string{"https://app.example.com"},
+		AllowedMethods:   []string{"GET", "post"},
+		AllowedHeaders:   []string{"content-type", "X-Request-ID"},
+		AllowCredentials: true,
+		MaxAge:           12 * time.Hour,
+	}
+	anyMethod := base
+	anyMethod.AllowedMethods = []string{"*"}
+	anyHeader := base
+	anyHeader.AllowedHeaders = []string{"*"}
+
+	tests := []struct {
+		name        string
+		cfg         config.CORSConfig
+		method      string
+		headers     string
+		wantStatus  int
+		wantMethods string
+		wantHeaders string
+	}{
+		{
+			name:        "listed method and headers",
+			cfg:         base,
+			method:      "POST",
+			headers:     "Content-Type, x-request-id",
+			wantStatus:  http.StatusNoContent,
+			wantMethods: "GET, POST",
+			wantHeaders: "Content-Type, X-Request-Id",
+		},
+		{name: "unlisted method", cfg: base, method: "DELETE", wantStatus: http.StatusForbidden},
+		{name: "unlisted header", cfg: base, method: "GET", headers: "X-Custom", wantStatus: http.StatusForbidden},
+		{
+			name:        "any method reflects the request",
+			cfg:         anyMethod,
+			method:      "PATCH",
+			wantStatus:  http.StatusNoContent,
+			wantMethods: "PATCH",
+			wantHeaders: "Content-Type, X-Request-Id",
+		},
+		{
+			name:        "any header reflects the request",
+			cfg:         anyHeader,
+			method:      "GET",
+			headers:     "X-Custom",
+			wantStatus:  http.StatusNoContent,
+			wantMethods: "GET, POST",
+			wantHeaders: "X-Custom",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headers := map[string]string{
+				"Origin":                        "https://app.example.com",
+				"Access-Control-Request-Method": tt.method,
+			}
+			if tt.headers != "" {
+				headers["Access-Control-Request-Headers"] = tt.headers
+			}
+			rec := corsRequest(t, tt.cfg, http.MethodOptions, headers)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusNoContent {
+				return
+			}
+			h := rec.Header()
+			if got := h.Get("Access-Control-Allow-Methods"); got != tt.wantMethods {
+				t.Errorf("Allow-Methods = %q, want %q", got, tt.wantMethods)
+			}
+			if got := h.Get("Access-Control-Allow-Headers"); got != tt.wantHeaders {
+				t.Errorf("Allow-Headers = %q, want %q", got, tt.wantHeaders)
+			}
+			if got := h.Get("Access-Control-Allow-Origin"); got != "https://app.example.com" {
+				t.Errorf("Allow-Origin = %q", got)
+			}
+			if got := h.Get("Access-Control-Allow-Credentials"); got != "true" {
+				t.Errorf("Allow-Credentials = %q", got)
+			}
+			if got := h.Get("Access-Control-Max-Age"); got != "43200" {
+				t.Errorf("Max-Age = %q", got)
+			}
+		})
+	}
+}
+
+func TestCORSPreflightAnyOrigin(t *testing.T) {
+	rec := corsRequest(t, config.CORSConfig{
+		AllowedOrigins:   []string{"*"},
+		AllowedMethods:   []string{"*"},
+		AllowCredentials: true,
+	}, http.MethodOptions, map[string]string{
+		"Origin":                        "https://evil.test",
+		"Access-Control-Request-Method": "put",
+	})
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want 204", rec.Code)
+	}
+	h := rec.Header()
+	if got := h.Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Errorf("Allow-Origin = %q, want *", got)
+	}
+	if got := h.Get("Access-Control-Allow-Credentials"); got != "" {
+		t.Errorf("Allow-Credentials = %q for the * origin", got)
+	}
+	if got := h.Get("Access-Control-Allow-Methods"); got != "PUT" {
+		t.Errorf("Allow-Methods = %q, want PUT", got)
+	}
+}
+
+func TestCORSSameOrigin(t *testing.T) {
+	rec := corsRequest(t, config.CORSConfig{AllowedOrigins: []string{"*"}, AllowedMethods: []string{"GET"}}, http.MethodGet, nil)
+	if rec.Code != http.StatusOK || rec.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("status = %d, Allow-Origin = %q; want no CORS headers without Origin",
+			rec.Code, rec.Header().Get("Access-Control-Allow-Origin"))
+	}
+	if got := rec.Header().Values("Vary"); len(got) == 0 || got[0] != "Origin" {
+		t.Errorf("Vary = %q, want Origin", got)
+	}
+}
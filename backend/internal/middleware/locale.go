@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"artisan-coder/pkg/i18n"
+)
+
+const (
+	localeQueryParam = "lang"
+	localeCookie     = "lang"
+)
+
+// Locale 协商请求语言
+// 优先级：用户偏好（?lang= 或 lang cookie） > Accept-Language > 默认英文
+func Locale() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		locale, ok := preferredLocale(c)
+		if !ok {
+			locale = i18n.Negotiate(c.GetHeader("Accept-Language"))
+		}
+
+		c.Set(i18n.GinKey, locale)
+		c.Request = c.Request.WithContext(i18n.WithLocale(c.Request.Context(), locale))
+		header := c.Writer.Header()
+		header.Set("Content-Language", string(locale))
+		// 响应随 Accept-Language 变化，缓存需区分
+		header.Add("Vary", "Accept-Language")
+		c.Next()
+	}
+}
+
+// preferredLocale 读取用户显式指定的语言
+func preferredLocale(c *gin.Context) (i18n.Locale, bool) {
+	if lang := c.Query(localeQueryParam); lang != "" {
+		if locale, ok := i18n.Parse(lang); ok {
+			return locale, true
+		}
+	}
+	if lang, err := c.Cookie(localeCookie); err == nil {
+		if locale, ok := i18n.Parse(lang); ok {
+			return locale, true
+		}
+	}
+	return "", false
+}
@@ -1,13 +1,17 @@
 package middleware
 
 import (
-	"log"
+	"log/slog"
 	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
-func Logger() gin.HandlerFunc {
+// Logger 结构化访问日志
+// 5xx 记为 error，4xx 记为 warn，其余为 info
+func Logger(logger *slog.Logger) gin.HandlerFunc {
+	logger = logger.With("component", "http")
+
 	return func(c *gin.Context) {
 		start := time.Now()
 		path := c.Request.URL.Path
@@ -15,16 +19,36 @@ func Logger() gin.HandlerFunc {
 
 		c.Next()
 
-		end := time.Now()
-		latency := end.Sub(start)
-
-		log.Printf("[%s] %s %s | Status: %d | Latency: %v | IP: %s",
-			c.Request.Method,
-			path,
-			query,
-			c.Writer.Status(),
-			latency,
-			c.ClientIP(),
-		)
+		latency := time.Since(start)
+		status := c.Writer.Status()
+
+		attrs := []slog.Attr{
+			slog.String("method", c.Request.Method),
+			slog.String("path", path),
+			slog.String("route", c.FullPath()),
+			slog.String("query", query),
+			slog.Int("status", status),
+			slog.Duration("latency", latency),
+			slog.Int("size", c.Writer.Size()),
+			slog.String("ip", c.ClientIP()),
+			slog.String("user_agent", c.Request.UserAgent()),
+		}
+		if userID, ok := GetUserID(c); ok {
+			attrs = append(attrs, slog.String("user_id", userID))
+		}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, slog.String("errors", c.Errors.String()))
+		}
+
+		level := slog.LevelInfo
+		switch {
+		case status >= 500:
+			level = slog.LevelError
+		case status >= 400:
+			level = slog.LevelWarn
+		}
+
+		// 请求 ID 由 logger 的 context handler 从请求 context 中附加
+		logger.LogAttrs(c.Request.Context(), level, "http request", attrs...)
 	}
 }
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"artisan-coder/internal/metrics"
+)
+
+// unmatchedRoute 未匹配路由的标签值，避免路径基数爆炸
+const unmatchedRoute = "unmatched"
+
+// Metrics 按路由模板记录请求数和延迟
+func Metrics(m *metrics.Metrics) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		status := strconv.Itoa(c.Writer.Status())
+
+		m.HTTPRequests.WithLabelValues(c.Request.Method, route, status).Inc()
+		m.HTTPDuration.WithLabelValues(c.Request.Method, route, status).Observe(time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"artisan-coder/internal/ratelimit"
+	"artisan-coder/pkg/i18n"
+	"artisan-coder/pkg/response"
+)
+
+// apiKeyHeader API Token 请求头
+const apiKeyHeader = "X-API-Key"
+
+// RateLimit 路由组限流中间件
+// 组未配置或限流关闭时直接放行；存储故障时放行并记录日志
+func RateLimit(registry *ratelimit.Registry, group string, logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rule, ok := registry.Rule(group)
+		if !ok {
+			c.Next()
+			return
+		}
+
+		key := group + ":" + rateLimitKey(c, rule.Key)
+		result, err := rule.Limiter.Allow(c.Request.Context(), key)
+		if err != nil {
+			logger.WarnContext(c.Request.Context(), "Rate limit check failed", "group", group, "error", err)
+			c.Next()
+			return
+		}
+
+		header := c.Writer.Header()
+		header.Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+		header.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		header.Set("RateLimit-Reset", seconds(result.ResetAfter))
+
+		if !result.Allowed {
+			header.Set("Retry-After", seconds(result.RetryAfter))
+			response.TooManyRequests(c, i18n.KeyTooManyRequests)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// rateLimitKey 按规则提取限流键
+// user 未登录、token 缺失时回退到 IP
+func rateLimitKey(c *gin.Context, kind string) string {
+	switch kind {
+	case ratelimit.KeyUser:
+		if userID, ok := GetUserID(c); ok {
+			return "user:" + userID
+		}
+	case ratelimit.KeyToken:
+		token := c.GetHeader(apiKeyHeader)
+		if token == "" {
+			token = strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		}
+		if token != "" {
+			// 不在存储中保存明文 Token
+			sum := sha256.Sum256([]byte(token))
+			return "token:" + hex.EncodeToString(sum[:16])
+		}
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// seconds 向上取整到秒
+func seconds(d time.Duration) string {
+	if d <= 0 {
+		return "0"
+	}
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"log/slog"
+	"runtime/debug"
+
+	"github.com/gin-gonic/gin"
+
+	"artisan-coder/pkg/response"
+)
+
+// Recovery 捕获 panic 并以结构化日志记录堆栈
+func Recovery(logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				logger.ErrorContext(c.Request.Context(), "panic recovered",
+					"panic", rec,
+					"stack", string(debug.Stack()),
+				)
+				if !c.Writer.Written() {
+					response.InternalError(c)
+				}
+				c.Abort()
+			}
+		}()
+		c.Next()
+	}
+}
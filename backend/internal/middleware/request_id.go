@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"artisan-coder/pkg/requestid"
+)
+
+// RequestID 接收或生成 X-Request-ID
+// 请求 ID 同时写入 gin 上下文、请求 context 和响应头
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(requestid.Header)
+		if !requestid.Valid(id) {
+			id = requestid.New()
+		}
+
+		c.Set(requestid.GinKey, id)
+		c.Request = c.Request.WithContext(requestid.WithContext(c.Request.Context(), id))
+		c.Header(requestid.Header, id)
+		c.Next()
+	}
+}
+
+// GetRequestID 从上下文获取请求 ID
+func GetRequestID(c *gin.Context) string {
+	return c.GetString(requestid.GinKey)
+}
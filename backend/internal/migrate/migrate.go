@@ -0,0 +1,333 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"gorm.io/gorm"
+)
+
+// 迁移文件命名：000001_create_users_table.up.sql / .down.sql
+// 与 golang-migrate 保持一致，schema_migrations 表结构也兼容
+var fileNamePattern = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// lockKey Postgres 咨询锁的键，多实例同时启动时串行执行迁移
+const lockKey int64 = 0x61727469_73616e00 // "artisan\x00"
+
+// Migration 单个版本的迁移
+type Migration struct {
+	Version uint64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status 迁移状态
+type Status struct {
+	Current uint64      // 当前版本，0 表示未执行过
+	Dirty   bool        // 上次迁移中途失败
+	Latest  uint64      // 迁移文件中的最新版本
+	Pending []Migration // 待执行的迁移
+}
+
+// Migrator 数据库迁移执行器
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+	log        *slog.Logger
+}
+
+// New 从文件系统加载迁移并创建执行器
+func New(db *gorm.DB, source fs.FS, log *slog.Logger) (*Migrator, error) {
+	migrations, err := Load(source)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations, log: log}, nil
+}
+
+// Load 读取并按版本排序迁移文件
+func Load(source fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(source, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	byVersion := make(map[uint64]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		m := fileNamePattern.FindStringSubmatch(entry.Name())
+		if m == nil {
+			continue
+		}
+
+		version, err := strconv.ParseUint(m[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %s: %w", entry.Name(), err)
+		}
+		content, err := fs.ReadFile(source, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = migration
+		} else if migration.Name != m[2] {
+			return nil, fmt.Errorf("conflicting names for migration %d: %s, %s", version, migration.Name, m[2])
+		}
+
+		if m[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// Latest 迁移文件中的最新版本
+func (m *Migrator) Latest() uint64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Status 返回当前迁移状态
+// 只读，不创建 schema_migrations 表，可用于就绪检查
+func (m *Migrator) Status(ctx context.Context) (Status, error) {
+	return m.status(m.db.WithContext(ctx))
+}
+
+func (m *Migrator) status(db *gorm.DB) (Status, error) {
+	current, dirty, err := readVersion(db)
+	if err != nil {
+		return Status{}, err
+	}
+
+	status := Status{Current: current, Dirty: dirty, Latest: m.Latest()}
+	for _, migration := range m.migrations {
+		if migration.Version > current {
+			status.Pending = append(status.Pending, migration)
+		}
+	}
+	return status, nil
+}
+
+// Up 执行所有待执行的迁移，返回执行数量
+// 持有咨询锁期间读取版本，其他实例等待后看到的已是最新版本
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	applied := 0
+	err := m.locked(ctx, func(db *gorm.DB) error {
+		if err := ensureTable(db); err != nil {
+			return err
+		}
+		status, err := m.status(db)
+		if err != nil {
+			return err
+		}
+		if status.Dirty {
+			return fmt.Errorf("database is dirty at version %d, fix it manually", status.Current)
+		}
+
+		for _, migration := range status.Pending {
+			if err := apply(db, migration.Version, migration.Up); err != nil {
+				return fmt.Errorf("migration %d_%s up failed: %w", migration.Version, migration.Name, err)
+			}
+			m.log.Info("Migration applied", "version", migration.Version, "name", migration.Name)
+			applied++
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down 回滚 steps 个版本
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	rolledBack := 0
+	err := m.locked(ctx, func(db *gorm.DB) error {
+		if err := ensureTable(db); err != nil {
+			return err
+		}
+		status, err := m.status(db)
+		if err != nil {
+			return err
+		}
+		if status.Dirty {
+			return fmt.Errorf("database is dirty at version %d, fix it manually", status.Current)
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && rolledBack < steps; i-- {
+			migration := m.migrations[i]
+			if migration.Version > status.Current {
+				continue
+			}
+			if migration.Down == "" {
+				return fmt.Errorf("migration %d_%s has no down file", migration.Version, migration.Name)
+			}
+
+			var previous uint64
+			if i > 0 {
+				previous = m.migrations[i-1].Version
+			}
+			if err := apply(db, previous, migration.Down); err != nil {
+				return fmt.Errorf("migration %d_%s down failed: %w", migration.Version, migration.Name, err)
+			}
+			m.log.Info("Migration rolled back", "version", migration.Version, "name", migration.Name)
+			rolledBack++
+		}
+		return nil
+	})
+	return rolledBack, err
+}
+
+// Baseline 将未记录版本的数据库标记为 version，不执行迁移 SQL
+// 用于接管已有表结构的数据库，如旧版 AutoMigrate 创建的库
+func (m *Migrator) Baseline(ctx context.Context, version uint64) error {
+	if !m.has(version) {
+		return fmt.Errorf("unknown migration version %d", version)
+	}
+	return m.locked(ctx, func(db *gorm.DB) error {
+		if err := ensureTable(db); err != nil {
+			return err
+		}
+		status, err := m.status(db)
+		if err != nil {
+			return err
+		}
+		if status.Current != 0 || status.Dirty {
+			return fmt.Errorf("database is already at version %d", status.Current)
+		}
+		if err := writeVersion(db, version, false); err != nil {
+			return err
+		}
+		m.log.Info("Migration baseline set", "version", version)
+		return nil
+	})
+}
+
+func (m *Migrator) has(version uint64) bool {
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			return true
+		}
+	}
+	return false
+}
+
+// locked 在单个连接上持有咨询锁执行 fn
+// 会话级咨询锁绑定连接，因此锁、迁移和解锁必须使用同一连接
+func (m *Migrator) locked(ctx context.Context, fn func(db *gorm.DB) error) error {
+	return m.db.WithContext(ctx).Connection(func(conn *gorm.DB) error {
+		if conn.Dialector.Name() != "postgres" {
+			return fn(conn)
+		}
+
+		if err := conn.Exec("SELECT pg_advisory_lock(?)", lockKey).Error; err != nil {
+			return fmt.Errorf("failed to acquire migration lock: %w", err)
+		}
+		defer func() {
+			// 使用独立 context，避免调用方取消后锁无法释放
+			if err := conn.WithContext(context.Background()).Exec("SELECT pg_advisory_unlock(?)", lockKey).Error; err != nil {
+				m.log.Error("Failed to release migration lock", "error", err)
+			}
+		}()
+
+		return fn(conn)
+	})
+}
+
+// apply 在事务中执行迁移 SQL 并更新版本
+// Postgres 的 DDL 支持事务，失败时整体回滚，不会留下 dirty 状态
+func apply(db *gorm.DB, version uint64, sql string) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(sql).Error; err != nil {
+			return err
+		}
+		return writeVersion(tx, version, false)
+	})
+}
+
+func ensureTable(db *gorm.DB) error {
+	return db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version BIGINT NOT NULL PRIMARY KEY,
+		dirty BOOLEAN NOT NULL
+	)`).Error
+}
+
+// readVersion 读取当前版本，schema_migrations 表不存在时视为 0
+func readVersion(db *gorm.DB) (uint64, bool, error) {
+	if !db.Migrator().HasTable("schema_migrations") {
+		return 0, false, nil
+	}
+
+	var rows []struct {
+		Version uint64
+		Dirty   bool
+	}
+	if err := db.Raw("SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&rows).Error; err != nil {
+		return 0, false, fmt.Errorf("failed to read migration version: %w", err)
+	}
+	if len(rows) == 0 {
+		return 0, false, nil
+	}
+	return rows[0].Version, rows[0].Dirty, nil
+}
+
+func writeVersion(db *gorm.DB, version uint64, dirty bool) error {
+	if err := db.Exec("DELETE FROM schema_migrations").Error; err != nil {
+		return err
+	}
+	if version == 0 {
+		return nil
+	}
+	return db.Exec("INSERT INTO schema_migrations (version, dirty) VALUES (?, ?)", version, dirty).Error
+}
+
+// Create 在 dir 下创建下一个版本的空迁移文件
+func Create(dir, name string) (string, string, error) {
+	if !regexp.MustCompile(`^[a-z0-9_]+$`).MatchString(name) {
+		return "", "", fmt.Errorf("invalid migration name %q, use lower_snake_case", name)
+	}
+
+	migrations, err := Load(os.DirFS(dir))
+	if err != nil {
+		return "", "", err
+	}
+	var next uint64 = 1
+	if len(migrations) > 0 {
+		next = migrations[len(migrations)-1].Version + 1
+	}
+
+	base := fmt.Sprintf("%06d_%s", next, name)
+	up := filepath.Join(dir, base+".up.sql")
+	down := filepath.Join(dir, base+".down.sql")
+	for _, file := range []string{up, down} {
+		if err := os.WriteFile(file, nil, 0o644); err != nil {
+			return "", "", err
+		}
+	}
+	return up, down, nil
+}
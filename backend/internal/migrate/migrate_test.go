@@ -0,0 +1,102 @@
+package migrate_test
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"testing/fstest"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"artisan-coder/internal/migrate"
+)
+
+var source = fstest.MapFS{
+	"000001_create_a.up.sql":   {Data: []byte("CREATE TABLE a (id INTEGER)")},
+	"000001_create_a.down.sql": {Data: []byte("DROP TABLE a")},
+	"000002_create_b.up.sql":   {Data: []byte("CREATE TABLE b (id INTEGER)")},
+	"000002_create_b.down.sql": {Data: []byte("DROP TABLE b")},
+}
+
+func newMigrator(t *testing.T) (*migrate.Migrator, *gorm.DB) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	m, err := migrate.New(db, source, slog.New(slog.DiscardHandler))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m, db
+}
+
+func TestStatusIsReadOnly(t *testing.T) {
+	m, db := newMigrator(t)
+
+	status, err := m.Status(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Current != 0 || status.Latest != 2 || len(status.Pending) != 2 {
+		t.Errorf("status = %+v", status)
+	}
+	if db.Migrator().HasTable("schema_migrations") {
+		t.Error("Status created schema_migrations")
+	}
+}
+
+func TestUpDown(t *testing.T) {
+	m, db := newMigrator(t)
+	ctx := context.Background()
+
+	if n, err := m.Up(ctx); err != nil || n != 2 {
+		t.Fatalf("Up = %d, %v", n, err)
+	}
+	if n, err := m.Up(ctx); err != nil || n != 0 {
+		t.Fatalf("second Up = %d, %v", n, err)
+	}
+	if n, err := m.Down(ctx, 1); err != nil || n != 1 {
+		t.Fatalf("Down = %d, %v", n, err)
+	}
+	if db.Migrator().HasTable("b") {
+		t.Error("Down did not drop b")
+	}
+	status, err := m.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Current != 1 {
+		t.Errorf("Current = %d, want 1", status.Current)
+	}
+}
+
+func TestBaseline(t *testing.T) {
+	m, db := newMigrator(t)
+	ctx := context.Background()
+
+	// 表已由其他方式创建
+	if err := db.Exec("CREATE TABLE a (id INTEGER)").Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Baseline(ctx, 3); err == nil {
+		t.Error("Baseline accepted an unknown version")
+	}
+	if err := m.Baseline(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Baseline(ctx, 1); err == nil {
+		t.Error("Baseline accepted an already versioned database")
+	}
+
+	n, err := m.Up(ctx)
+	if err != nil || n != 1 {
+		t.Fatalf("Up after baseline = %d, %v", n, err)
+	}
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// RateLimit 限流计数，供 Postgres 限流存储使用
+type RateLimit struct {
+	Key       string    `gorm:"type:varchar(255);primaryKey" json:"key"`
+	Value     int64     `gorm:"not null;default:0" json:"value"`
+	ExpiresAt time.Time `gorm:"not null;index" json:"expiresAt"`
+}
+
+func (RateLimit) TableName() string {
+	return "rate_limits"
+}
@@ -7,13 +7,30 @@ import (
 	"gorm.io/gorm"
 )
 
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
 type User struct {
-	ID           uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	ID           uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
 	Username     string    `gorm:"type:varchar(50);not null;uniqueIndex" json:"username"`
 	Email        string    `gorm:"type:varchar(255);not null;uniqueIndex" json:"email"`
 	PasswordHash string    `gorm:"type:varchar(255);not null" json:"-"`
-	CreatedAt    time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"createdAt"`
-	UpdatedAt    time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"updatedAt"`
+	Role         string    `gorm:"type:varchar(20);not null;default:'user'" json:"role"`
+	CreatedAt    time.Time `json:"createdAt"` // 由 GORM 按注入的 Clock 填充
+	UpdatedAt    time.Time `json:"updatedAt"`
+
+	// 软删除，GORM 查询默认排除已删除的用户；邮箱和用户名仍保持占用
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+	DisabledAt  *time.Time     `json:"disabledAt,omitempty"` // 非空时禁止登录，已签发的令牌同样失效
+	LastLoginAt *time.Time     `json:"lastLoginAt,omitempty"`
+	CreatedBy   *uuid.UUID     `gorm:"type:uuid" json:"createdBy,omitempty"` // 创建者，自助注册为空
+}
+
+// Disabled 账号是否已被禁用
+func (u *User) Disabled() bool {
+	return u.DisabledAt != nil
 }
 
 func (User) TableName() string {
@@ -21,9 +38,10 @@ func (User) TableName() string {
 }
 
 // BeforeCreate GORM hook
+// ID 在应用侧由 IDGenerator 生成（见 database.idPlugin），不依赖数据库的 uuid_generate_v4()
 func (u *User) BeforeCreate(tx *gorm.DB) error {
-	if u.ID == uuid.Nil {
-		u.ID = uuid.New()
+	if u.Role == "" {
+		u.Role = RoleUser
 	}
 	return nil
 }
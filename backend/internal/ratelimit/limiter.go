@@ -0,0 +1,200 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"artisan-coder/pkg/clock"
+)
+
+const (
+	AlgorithmTokenBucket   = "token_bucket"
+	AlgorithmSlidingWindow = "sliding_window"
+)
+
+// Result 单次限流判定结果
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	ResetAfter time.Duration // 额度完全恢复所需时间
+	RetryAfter time.Duration // 被拒绝时建议的重试间隔
+}
+
+// Limiter 限流算法
+type Limiter interface {
+	Allow(ctx context.Context, key string) (Result, error)
+}
+
+// casRetries 令牌桶 CAS 冲突时的最大重试次数
+const casRetries = 5
+
+var errContention = errors.New("rate limit store contention")
+
+// TokenBucket 令牌桶限流，以 GCRA 实现
+// 每 interval 补充一个令牌，最多累积 burst 个
+type TokenBucket struct {
+	store    Store
+	interval time.Duration
+	burst    int
+	clock    clock.Clock
+}
+
+// NewTokenBucket 创建令牌桶：每个 window 补充 limit 个令牌，桶容量为 burst
+func NewTokenBucket(store Store, clk clock.Clock, limit int, window time.Duration, burst int) *TokenBucket {
+	if burst <= 0 {
+		burst = limit
+	}
+	return &TokenBucket{
+		store:    store,
+		interval: window / time.Duration(limit),
+		burst:    burst,
+		clock:    clk,
+	}
+}
+
+func (l *TokenBucket) Allow(ctx context.Context, key string) (Result, error) {
+	key = "tb:" + key
+	capacity := time.Duration(l.burst) * l.interval
+
+	for i := 0; i < casRetries; i++ {
+		now := l.clock.Now().UnixNano()
+
+		// tat: theoretical arrival time，桶恰好填满的时间点
+		tat, err := l.store.Get(ctx, key)
+		if err != nil {
+			return Result{}, err
+		}
+
+		base := max(tat, now)
+		newTAT := base + int64(l.interval)
+		allowAt := newTAT - int64(capacity)
+
+		if now < allowAt {
+			return Result{
+				Allowed:    false,
+				Limit:      l.burst,
+				Remaining:  0,
+				ResetAfter: time.Duration(tat - now),
+				RetryAfter: time.Duration(allowAt - now),
+			}, nil
+		}
+
+		ok, err := l.store.CompareAndSwap(ctx, key, tat, newTAT, time.Unix(0, newTAT))
+		if err != nil {
+			return Result{}, err
+		}
+		if !ok {
+			continue
+		}
+
+		return Result{
+			Allowed:    true,
+			Limit:      l.burst,
+			Remaining:  int((int64(capacity) - (newTAT - now)) / int64(l.interval)),
+			ResetAfter: time.Duration(newTAT - now),
+		}, nil
+	}
+
+	return Result{}, errContention
+}
+
+// SlidingWindow 滑动窗口计数限流
+// 以上一窗口计数按重叠比例加权，近似真实滑动窗口
+type SlidingWindow struct {
+	store  Store
+	limit  int
+	window time.Duration
+	clock  clock.Clock
+}
+
+// NewSlidingWindow 创建滑动窗口：每个 window 内最多 limit 次
+func NewSlidingWindow(store Store, clk clock.Clock, limit int, window time.Duration) *SlidingWindow {
+	return &SlidingWindow{
+		store:  store,
+		limit:  limit,
+		window: window,
+		clock:  clk,
+	}
+}
+
+func (l *SlidingWindow) Allow(ctx context.Context, key string) (Result, error) {
+	now := l.clock.Now()
+	current := now.Truncate(l.window)
+	previous := current.Add(-l.window)
+	elapsed := now.Sub(current)
+
+	currentKey := "sw:" + key + ":" + strconv.FormatInt(current.Unix(), 10)
+	previousKey := "sw:" + key + ":" + strconv.FormatInt(previous.Unix(), 10)
+
+	prevCount, err := l.store.Get(ctx, previousKey)
+	if err != nil {
+		return Result{}, err
+	}
+
+	count, err := l.store.Increment(ctx, currentKey, 1, current.Add(2*l.window))
+	if err != nil {
+		return Result{}, err
+	}
+
+	weight := 1 - float64(elapsed)/float64(l.window)
+	estimated := float64(prevCount)*weight + float64(count)
+	resetAfter := l.window - elapsed
+
+	if estimated > float64(l.limit) {
+		// 被拒绝的请求不计入窗口
+		if _, err := l.store.Increment(ctx, currentKey, -1, current.Add(2*l.window)); err != nil {
+			return Result{}, err
+		}
+		return Result{
+			Allowed:    false,
+			Limit:      l.limit,
+			Remaining:  0,
+			ResetAfter: resetAfter,
+			RetryAfter: l.retryAfter(prevCount, count-1, elapsed),
+		}, nil
+	}
+
+	return Result{
+		Allowed:    true,
+		Limit:      l.limit,
+		Remaining:  max(0, int(math.Floor(float64(l.limit)-estimated))),
+		ResetAfter: resetAfter,
+	}, nil
+}
+
+// retryAfter 估算上一窗口权重衰减到可再放行一次所需的时间
+func (l *SlidingWindow) retryAfter(prevCount, count int64, elapsed time.Duration) time.Duration {
+	remainingWindow := l.window - elapsed
+	if prevCount == 0 || count+1 > int64(l.limit) {
+		return remainingWindow
+	}
+
+	// prev * (1 - t/window) + count + 1 <= limit
+	need := 1 - float64(int64(l.limit)-count-1)/float64(prevCount)
+	wait := time.Duration(need*float64(l.window)) - elapsed
+	if wait <= 0 || wait > remainingWindow {
+		return remainingWindow
+	}
+	return wait
+}
+
+// NewLimiter 按算法名创建限流器
+func NewLimiter(store Store, clk clock.Clock, algorithm string, limit int, window time.Duration, burst int) (Limiter, error) {
+	if limit <= 0 || window <= 0 {
+		return nil, fmt.Errorf("invalid rate limit %d per %s", limit, window)
+	}
+
+	switch algorithm {
+	case AlgorithmTokenBucket:
+		return NewTokenBucket(store, clk, limit, window, burst), nil
+	case "", AlgorithmSlidingWindow:
+		return NewSlidingWindow(store, clk, limit, window), nil
+	default:
+		return nil, fmt.Errorf("unknown rate limit algorithm %q", algorithm)
+	}
+}
@@ -0,0 +1,177 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"artisan-coder/pkg/clock"
+)
+
+// allow 执行一次 Allow，检查是否放行
+func allow(t *testing.T, l Limiter, want bool) Result {
+	t.Helper()
+	res, err := l.Allow(context.Background(), "k")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Allowed != want {
+		t.Fatalf("Allowed = %t, want %t (%+v)", res.Allowed, want, res)
+	}
+	return res
+}
+
+func TestTokenBucket(t *testing.T) {
+	clk := clock.NewFake(testStart)
+	// 每秒补充一个令牌，最多 3 个
+	l := NewTokenBucket(NewMemoryStore(clk), clk, 10, 10*time.Second, 3)
+
+	for want := 2; want >= 0; want-- {
+		res := allow(t, l, true)
+		if res.Remaining != want || res.Limit != 3 {
+			t.Errorf("Remaining = %d, Limit = %d; want %d, 3", res.Remaining, res.Limit, want)
+		}
+	}
+	res := allow(t, l, false)
+	if res.RetryAfter != time.Second || res.ResetAfter != 3*time.Second {
+		t.Errorf("RetryAfter = %s, ResetAfter = %s; want 1s, 3s", res.RetryAfter, res.ResetAfter)
+	}
+
+	clk.Advance(500 * time.Millisecond)
+	if res := allow(t, l, false); res.RetryAfter != 500*time.Millisecond {
+		t.Errorf("RetryAfter = %s, want 500ms", res.RetryAfter)
+	}
+
+	// 补充一个令牌
+	clk.Advance(500 * time.Millisecond)
+	allow(t, l, true)
+	allow(t, l, false)
+
+	// 空闲再久也只累积 burst 个
+	clk.Advance(time.Hour)
+	for i := 0; i < 3; i++ {
+		allow(t, l, true)
+	}
+	allow(t, l, false)
+}
+
+func TestTokenBucketDefaultBurst(t *testing.T) {
+	clk := clock.NewFake(testStart)
+	l := NewTokenBucket(NewMemoryStore(clk), clk, 2, time.Minute, 0)
+	allow(t, l, true)
+	allow(t, l, true)
+	if res := allow(t, l, false); res.RetryAfter != 30*time.Second {
+		t.Errorf("RetryAfter = %s, want 30s", res.RetryAfter)
+	}
+}
+
+// conflictStore 模拟其他实例在 Get 和 CompareAndSwap 之间抢先写入
+type conflictStore struct {
+	Store
+	conflicts int
+	compete   func() // 冲突时先执行的竞争请求
+}
+
+func (s *conflictStore) CompareAndSwap(ctx context.Context, key string, old, new int64, expiresAt time.Time) (bool, error) {
+	if s.conflicts > 0 {
+		s.conflicts--
+		if s.compete != nil {
+			s.compete()
+		}
+		return false, nil
+	}
+	return s.Store.CompareAndSwap(ctx, key, old, new, expiresAt)
+}
+
+func TestTokenBucketCompareAndSwapConflict(t *testing.T) {
+	clk := clock.NewFake(testStart)
+	mem := NewMemoryStore(clk)
+	other := NewTokenBucket(mem, clk, 10, 10*time.Second, 3)
+
+	store := &conflictStore{Store: mem, conflicts: 1}
+	store.compete = func() { allow(t, other, true) }
+	l := NewTokenBucket(store, clk, 10, 10*time.Second, 3)
+
+	// 重试后基于对方写入的值扣减，两次请求各消耗一个令牌
+	if res := allow(t, l, true); res.Remaining != 1 {
+		t.Errorf("Remaining = %d after a conflict, want 1", res.Remaining)
+	}
+	allow(t, l, true)
+	allow(t, l, false)
+}
+
+func TestTokenBucketContention(t *testing.T) {
+	clk := clock.NewFake(testStart)
+	store := &conflictStore{Store: NewMemoryStore(clk), conflicts: casRetries}
+	l := NewTokenBucket(store, clk, 10, 10*time.Second, 3)
+
+	if _, err := l.Allow(context.Background(), "k"); !errors.Is(err, errContention) {
+		t.Errorf("Allow = %v, want errContention", err)
+	}
+}
+
+func TestSlidingWindow(t *testing.T) {
+	clk := clock.NewFake(testStart)
+	l := NewSlidingWindow(NewMemoryStore(clk), clk, 4, 10*time.Second)
+
+	for want := 3; want >= 0; want-- {
+		if res := allow(t, l, true); res.Remaining != want {
+			t.Errorf("Remaining = %d, want %d", res.Remaining, want)
+		}
+	}
+	res := allow(t, l, false)
+	if res.RetryAfter != 10*time.Second || res.ResetAfter != 10*time.Second {
+		t.Errorf("RetryAfter = %s, ResetAfter = %s; want 10s, 10s", res.RetryAfter, res.ResetAfter)
+	}
+
+	// 下一窗口开始时上一窗口权重为 1；被拒绝的请求没有计入
+	clk.Advance(10 * time.Second)
+	res = allow(t, l, false)
+	if res.RetryAfter != 2500*time.Millisecond {
+		t.Errorf("RetryAfter = %s, want 2.5s", res.RetryAfter)
+	}
+
+	// 权重衰减到 0.75：4*0.75 + 1 = 4
+	clk.Advance(2500 * time.Millisecond)
+	allow(t, l, true)
+	allow(t, l, false)
+
+	// 再过两个窗口，计数全部过期
+	clk.Advance(20 * time.Second)
+	for i := 0; i < 4; i++ {
+		allow(t, l, true)
+	}
+}
+
+func TestNewLimiter(t *testing.T) {
+	clk := clock.NewFake(testStart)
+	store := NewMemoryStore(clk)
+
+	tests := []struct {
+		algorithm string
+		limit     int
+		window    time.Duration
+		want      string // 为空时期望返回错误
+	}{
+		{algorithm: "", limit: 1, window: time.Second, want: "*ratelimit.SlidingWindow"},
+		{algorithm: AlgorithmSlidingWindow, limit: 1, window: time.Second, want: "*ratelimit.SlidingWindow"},
+		{algorithm: AlgorithmTokenBucket, limit: 1, window: time.Second, want: "*ratelimit.TokenBucket"},
+		{algorithm: "leaky_bucket", limit: 1, window: time.Second},
+		{algorithm: AlgorithmTokenBucket, limit: 0, window: time.Second},
+		{algorithm: AlgorithmTokenBucket, limit: 1, window: 0},
+	}
+	for _, tt := range tests {
+		l, err := NewLimiter(store, clk, tt.algorithm, tt.limit, tt.window, 0)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("NewLimiter(%q, %d, %s) succeeded, want an error", tt.algorithm, tt.limit, tt.window)
+			}
+			continue
+		}
+		if got := fmt.Sprintf("%T", l); err != nil || got != tt.want {
+			t.Errorf("NewLimiter(%q) = %s, %v; want %s", tt.algorithm, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"artisan-coder/pkg/clock"
+)
+
+type memoryEntry struct {
+	value     int64
+	expiresAt time.Time
+}
+
+// MemoryStore 进程内计数存储，适用于单实例部署
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+	clock   clock.Clock
+}
+
+// NewMemoryStore 创建内存存储
+func NewMemoryStore(clk clock.Clock) *MemoryStore {
+	return &MemoryStore{
+		entries: make(map[string]memoryEntry),
+		clock:   clk,
+	}
+}
+
+func (s *MemoryStore) Increment(ctx context.Context, key string, delta int64, expiresAt time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := s.load(key)
+	entry.value += delta
+	entry.expiresAt = expiresAt
+	s.entries[key] = entry
+	return entry.value, nil
+}
+
+func (s *MemoryStore) Get(ctx context.Context, key string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.load(key).value, nil
+}
+
+func (s *MemoryStore) CompareAndSwap(ctx context.Context, key string, old, new int64, expiresAt time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.load(key).value != old {
+		return false, nil
+	}
+	s.entries[key] = memoryEntry{value: new, expiresAt: expiresAt}
+	return true, nil
+}
+
+func (s *MemoryStore) Cleanup(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now()
+	for key, entry := range s.entries {
+		if !entry.expiresAt.After(now) {
+			delete(s.entries, key)
+		}
+	}
+	return nil
+}
+
+// load 读取未过期的条目，调用方需持有锁
+func (s *MemoryStore) load(key string) memoryEntry {
+	entry, ok := s.entries[key]
+	if !ok || !entry.expiresAt.After(s.clock.Now()) {
+		return memoryEntry{}
+	}
+	return entry
+}
@@ -0,0 +1,74 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"artisan-coder/pkg/clock"
+)
+
+// PostgresStore 基于 rate_limits 表的计数存储，多实例共享限流状态
+// 过期判断使用注入的时钟而非数据库的 now()，与调用方计算 expiresAt 的时钟一致
+type PostgresStore struct {
+	db    *gorm.DB
+	clock clock.Clock
+}
+
+// NewPostgresStore 创建 Postgres 存储
+func NewPostgresStore(db *gorm.DB, clk clock.Clock) *PostgresStore {
+	return &PostgresStore{db: db, clock: clk}
+}
+
+func (s *PostgresStore) Increment(ctx context.Context, key string, delta int64, expiresAt time.Time) (int64, error) {
+	var value int64
+	err := s.db.WithContext(ctx).Raw(`
+		INSERT INTO rate_limits (key, value, expires_at) VALUES (?, ?, ?)
+		ON CONFLICT (key) DO UPDATE SET
+			value = CASE WHEN rate_limits.expires_at <= ? THEN EXCLUDED.value
+				ELSE rate_limits.value + EXCLUDED.value END,
+			expires_at = EXCLUDED.expires_at
+		RETURNING value`,
+		key, delta, expiresAt, s.clock.Now(),
+	).Scan(&value).Error
+	return value, err
+}
+
+func (s *PostgresStore) Get(ctx context.Context, key string) (int64, error) {
+	var values []int64
+	err := s.db.WithContext(ctx).Raw(
+		`SELECT value FROM rate_limits WHERE key = ? AND expires_at > ?`, key, s.clock.Now(),
+	).Scan(&values).Error
+	if err != nil || len(values) == 0 {
+		return 0, err
+	}
+	return values[0], nil
+}
+
+func (s *PostgresStore) CompareAndSwap(ctx context.Context, key string, old, new int64, expiresAt time.Time) (bool, error) {
+	var result *gorm.DB
+	if old == 0 {
+		// 不存在或已过期视为 0
+		result = s.db.WithContext(ctx).Exec(`
+			INSERT INTO rate_limits (key, value, expires_at) VALUES (?, ?, ?)
+			ON CONFLICT (key) DO UPDATE SET value = EXCLUDED.value, expires_at = EXCLUDED.expires_at
+			WHERE rate_limits.value = 0 OR rate_limits.expires_at <= ?`,
+			key, new, expiresAt, s.clock.Now(),
+		)
+	} else {
+		result = s.db.WithContext(ctx).Exec(`
+			UPDATE rate_limits SET value = ?, expires_at = ?
+			WHERE key = ? AND value = ? AND expires_at > ?`,
+			new, expiresAt, key, old, s.clock.Now(),
+		)
+	}
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (s *PostgresStore) Cleanup(ctx context.Context) error {
+	return s.db.WithContext(ctx).Exec(`DELETE FROM rate_limits WHERE expires_at <= ?`, s.clock.Now()).Error
+}
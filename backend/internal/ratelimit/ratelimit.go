@@ -0,0 +1,171 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/fx"
+	"gorm.io/gorm"
+
+	"artisan-coder/internal/config"
+	"artisan-coder/pkg/clock"
+)
+
+const (
+	StoreMemory   = "memory"
+	StorePostgres = "postgres"
+
+	KeyIP    = "ip"
+	KeyUser  = "user"
+	KeyToken = "token"
+)
+
+// cleanupInterval 过期计数清理间隔
+const cleanupInterval = time.Minute
+
+// Rule 路由组的限流规则
+type Rule struct {
+	Limiter Limiter
+	Key     string // ip, user, token
+}
+
+// Registry 按路由组名管理限流规则，配置热更新时整体替换
+type Registry struct {
+	store Store
+	clock clock.Clock
+	rules atomic.Pointer[ruleSet]
+}
+
+type ruleSet struct {
+	enabled bool
+	rules   map[string]Rule
+}
+
+// Rule 返回路由组的限流规则，未配置或限流关闭时返回 false
+func (r *Registry) Rule(group string) (Rule, bool) {
+	if r == nil {
+		return Rule{}, false
+	}
+	set := r.rules.Load()
+	if !set.enabled {
+		return Rule{}, false
+	}
+	rule, ok := set.rules[group]
+	return rule, ok
+}
+
+// Reload 按新配置重建限流规则，失败时保留原规则
+// 计数存储不变，已有计数在新规则下继续生效
+func (r *Registry) Reload(cfg config.RateLimitConfig) error {
+	set, err := buildRules(cfg, r.store, r.clock)
+	if err != nil {
+		return err
+	}
+	r.rules.Store(set)
+	return nil
+}
+
+// Module 返回限流模块的 FX 选项
+func Module() fx.Option {
+	return fx.Provide(
+		NewStore,
+		NewRegistry,
+	)
+}
+
+// NewStore 按配置创建计数存储，并注册过期清理任务
+// 清理间隔由 clk 计时，测试中拨动 Fake 即可触发
+func NewStore(lc fx.Lifecycle, cfg *config.Config, db *gorm.DB, clk clock.Clock, log *slog.Logger) (Store, error) {
+	var store Store
+	switch cfg.RateLimit.Store {
+	case "", StoreMemory:
+		store = NewMemoryStore(clk)
+	case StorePostgres:
+		store = NewPostgresStore(db, clk)
+	default:
+		return nil, fmt.Errorf("unknown rate limit store %q", cfg.RateLimit.Store)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+
+				for {
+					select {
+					case <-ctx.Done():
+						return
+					case <-clk.After(cleanupInterval):
+						if err := store.Cleanup(ctx); err != nil && ctx.Err() == nil {
+							log.Warn("Rate limit cleanup failed", "error", err)
+						}
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+			case <-stopCtx.Done():
+			}
+			return nil
+		},
+	})
+
+	return store, nil
+}
+
+// NewRegistry 根据配置为各路由组创建限流器，并订阅配置热更新
+func NewRegistry(cfg *config.Config, store Store, clk clock.Clock, w *config.Watcher, log *slog.Logger) (*Registry, error) {
+	registry := &Registry{store: store, clock: clk}
+	if err := registry.Reload(cfg.RateLimit); err != nil {
+		return nil, err
+	}
+
+	w.Subscribe(func(old, new *config.Config) {
+		if reflect.DeepEqual(old.RateLimit, new.RateLimit) {
+			return
+		}
+		if err := registry.Reload(new.RateLimit); err != nil {
+			log.Error("Failed to reload rate limit rules", "error", err)
+		}
+	})
+
+	return registry, nil
+}
+
+func buildRules(cfg config.RateLimitConfig, store Store, clk clock.Clock) (*ruleSet, error) {
+	set := &ruleSet{
+		enabled: cfg.Enabled,
+		rules:   make(map[string]Rule, len(cfg.Groups)),
+	}
+
+	for group, rule := range cfg.Groups {
+		limiter, err := NewLimiter(store, clk, rule.Algorithm, rule.Limit, rule.Window, rule.Burst)
+		if err != nil {
+			return nil, fmt.Errorf("rate limit group %q: %w", group, err)
+		}
+
+		key := rule.Key
+		switch key {
+		case "":
+			key = KeyIP
+		case KeyIP, KeyUser, KeyToken:
+		default:
+			return nil, fmt.Errorf("rate limit group %q: unknown key %q", group, rule.Key)
+		}
+
+		set.rules[group] = Rule{Limiter: limiter, Key: key}
+	}
+
+	return set, nil
+}
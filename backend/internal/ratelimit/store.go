@@ -0,0 +1,22 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Store 限流计数存储
+// 计数以 int64 保存，过期后视为 0；实现必须保证单个操作的原子性
+type Store interface {
+	// Increment 将 key 的值加 delta 并返回新值；key 不存在或已过期时从 0 开始
+	Increment(ctx context.Context, key string, delta int64, expiresAt time.Time) (int64, error)
+
+	// Get 返回 key 当前值，不存在或已过期时返回 0
+	Get(ctx context.Context, key string) (int64, error)
+
+	// CompareAndSwap 当 key 当前值等于 old（不存在视为 0）时设置为 new
+	CompareAndSwap(ctx context.Context, key string, old, new int64, expiresAt time.Time) (bool, error)
+
+	// Cleanup 清理已过期的计数
+	Cleanup(ctx context.Context) error
+}
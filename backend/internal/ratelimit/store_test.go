@@ -0,0 +1,77 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"artisan-coder/internal/database"
+	"artisan-coder/internal/database/databasetest"
+	"artisan-coder/pkg/clock"
+)
+
+// testStart 测试时钟的起点，远离数据库的当前时间，并与 10s 窗口对齐
+var testStart = time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// TestStore 两种存储的过期判断都跟随注入的时钟
+// Postgres 需设置 databasetest.PostgresURLEnv
+func TestStore(t *testing.T) {
+	stores := map[string]func(t *testing.T, clk clock.Clock) Store{
+		"memory": func(t *testing.T, clk clock.Clock) Store {
+			return NewMemoryStore(clk)
+		},
+		"postgres": func(t *testing.T, clk clock.Clock) Store {
+			return NewPostgresStore(databasetest.Open(t, database.DriverPostgres), clk)
+		},
+	}
+
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			if name == "postgres" {
+				databasetest.SkipUnavailable(t, database.DriverPostgres)
+			}
+			ctx := context.Background()
+			clk := clock.NewFake(testStart)
+			store := newStore(t, clk)
+			expiresAt := testStart.Add(time.Minute)
+
+			for want := int64(1); want <= 2; want++ {
+				got, err := store.Increment(ctx, "a", 1, expiresAt)
+				if err != nil || got != want {
+					t.Fatalf("Increment = %d, %v; want %d", got, err, want)
+				}
+			}
+			if ok, err := store.CompareAndSwap(ctx, "a", 1, 5, expiresAt); err != nil || ok {
+				t.Fatalf("CompareAndSwap with a stale old value = %t, %v; want false", ok, err)
+			}
+			if ok, err := store.CompareAndSwap(ctx, "a", 2, 5, expiresAt); err != nil || !ok {
+				t.Fatalf("CompareAndSwap = %t, %v; want true", ok, err)
+			}
+			if got, err := store.Get(ctx, "a"); err != nil || got != 5 {
+				t.Fatalf("Get = %d, %v; want 5", got, err)
+			}
+			if ok, err := store.CompareAndSwap(ctx, "b", 0, 7, expiresAt); err != nil || !ok {
+				t.Fatalf("CompareAndSwap on a missing key = %t, %v; want true", ok, err)
+			}
+
+			// 按时钟过期，与数据库时间无关
+			clk.Set(expiresAt)
+			if got, err := store.Get(ctx, "a"); err != nil || got != 0 {
+				t.Errorf("Get after expiry = %d, %v; want 0", got, err)
+			}
+			if got, err := store.Increment(ctx, "a", 1, expiresAt.Add(time.Minute)); err != nil || got != 1 {
+				t.Errorf("Increment after expiry = %d, %v; want 1", got, err)
+			}
+			if ok, err := store.CompareAndSwap(ctx, "b", 0, 3, expiresAt.Add(time.Minute)); err != nil || !ok {
+				t.Errorf("CompareAndSwap on an expired key = %t, %v; want true", ok, err)
+			}
+
+			if err := store.Cleanup(ctx); err != nil {
+				t.Fatal(err)
+			}
+			if got, err := store.Get(ctx, "a"); err != nil || got != 1 {
+				t.Errorf("Cleanup removed a live key: Get = %d, %v", got, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"artisan-coder/internal/database"
+)
+
+// ErrConstraintViolation 其他约束冲突（外键、检查、非空）
+var ErrConstraintViolation = errors.New("constraint violation")
+
+// ConstraintViolation 违反数据库约束的领域错误
+// Err 为领域哨兵错误，如 ErrEmailTaken，调用方用 errors.Is 判断
+type ConstraintViolation struct {
+	Kind       database.ConstraintKind
+	Constraint string // 约束名
+	Field      string // 冲突字段（列名）
+	Err        error
+	cause      error
+}
+
+func (e *ConstraintViolation) Error() string {
+	return fmt.Sprintf("%v (field %q, constraint %q)", e.Err, e.Field, e.Constraint)
+}
+
+// Unwrap 同时暴露领域错误和驱动错误，内存实现没有驱动错误
+func (e *ConstraintViolation) Unwrap() []error {
+	if e.cause == nil {
+		return []error{e.Err}
+	}
+	return []error{e.Err, e.cause}
+}
+
+// mapConstraintError 将约束冲突映射为领域错误
+// unique 为唯一约束列名到领域错误的映射，未列出的唯一约束映射为 conflict
+func mapConstraintError(err error, unique map[string]error, conflict error) error {
+	var ce *database.ConstraintError
+	if !errors.As(err, &ce) {
+		return err
+	}
+
+	v := &ConstraintViolation{
+		Kind:       ce.Kind,
+		Constraint: ce.Constraint,
+		Field:      ce.Column,
+		Err:        ErrConstraintViolation,
+		cause:      err,
+	}
+	if ce.Kind == database.ConstraintUnique {
+		v.Err = conflict
+		if domainErr, ok := unique[ce.Column]; ok {
+			v.Err = domainErr
+		}
+	}
+	return v
+}
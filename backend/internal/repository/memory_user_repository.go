@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"artisan-coder/internal/database"
+	"artisan-coder/internal/models"
+	"artisan-coder/pkg/clock"
+	"artisan-coder/pkg/idgen"
+)
+
+// memoryUserRepository 内存实现，行为与 GORM 实现一致，见 repositorytest
+// 读写均复制模型，调用方修改返回值不影响存储
+type memoryUserRepository struct {
+	clock clock.Clock
+	ids   idgen.IDGenerator
+
+	mu    sync.RWMutex
+	users map[uuid.UUID]models.User
+}
+
+// NewMemoryUserRepository 创建内存用户仓储，供测试使用
+func NewMemoryUserRepository(clk clock.Clock, ids idgen.IDGenerator) UserRepository {
+	return &memoryUserRepository{
+		clock: clk,
+		ids:   ids,
+		users: make(map[uuid.UUID]models.User),
+	}
+}
+
+func (r *memoryUserRepository) Create(ctx context.Context, user *models.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// 与 database.idPlugin、BeforeCreate 钩子和 GORM 的时间戳填充保持一致
+	if user.ID == uuid.Nil {
+		user.ID = r.ids.NewID()
+	}
+	if user.Role == "" {
+		user.Role = models.RoleUser
+	}
+	if _, ok := r.users[user.ID]; ok {
+		return &ConstraintViolation{
+			Kind:       database.ConstraintUnique,
+			Constraint: "users_pkey",
+			Field:      "id",
+			Err:        ErrUserAlreadyExists,
+		}
+	}
+	if err := r.checkUnique(user); err != nil {
+		return err
+	}
+
+	now := r.clock.Now()
+	if user.CreatedAt.IsZero() {
+		user.CreatedAt = now
+	}
+	if user.UpdatedAt.IsZero() {
+		user.UpdatedAt = now
+	}
+	r.users[user.ID] = *cloneUser(*user)
+	return nil
+}
+
+func (r *memoryUserRepository) FindByEmail(ctx context.Context, email string) (*models.User, error) {
+	return r.find(func(u *models.User) bool { return u.Email == email })
+}
+
+func (r *memoryUserRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	return r.find(func(u *models.User) bool { return u.ID == id })
+}
+
+func (r *memoryUserRepository) FindByUsername(ctx context.Context, username string) (*models.User, error) {
+	return r.find(func(u *models.User) bool { return u.Username == username })
+}
+
+// Update 与 GORM 的 Save 一致，记录不存在时插入
+func (r *memoryUserRepository) Update(ctx context.Context, user *models.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.checkUnique(user); err != nil {
+		return err
+	}
+
+	user.UpdatedAt = r.clock.Now()
+	if existing, ok := r.users[user.ID]; ok && user.CreatedAt.IsZero() {
+		user.CreatedAt = existing.CreatedAt
+	}
+	r.users[user.ID] = *cloneUser(*user)
+	return nil
+}
+
+func (r *memoryUserRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	u, ok := r.users[id]
+	if !ok || u.DeletedAt.Valid {
+		return ErrUserNotFound
+	}
+	u.DeletedAt = gorm.DeletedAt{Time: r.clock.Now(), Valid: true}
+	r.users[id] = u
+	return nil
+}
+
+func (r *memoryUserRepository) RecordLogin(ctx context.Context, id uuid.UUID, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	u, ok := r.users[id]
+	if !ok || u.DeletedAt.Valid {
+		return ErrUserNotFound
+	}
+	u.LastLoginAt = &at
+	r.users[id] = u
+	return nil
+}
+
+// find 返回第一个匹配的未删除用户
+func (r *memoryUserRepository) find(match func(*models.User) bool) (*models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, u := range r.users {
+		if !u.DeletedAt.Valid && match(&u) {
+			return cloneUser(u), nil
+		}
+	}
+	return nil, ErrUserNotFound
+}
+
+// cloneUser 深拷贝指针字段，避免调用方与存储共享
+func cloneUser(u models.User) *models.User {
+	if u.DisabledAt != nil {
+		t := *u.DisabledAt
+		u.DisabledAt = &t
+	}
+	if u.LastLoginAt != nil {
+		t := *u.LastLoginAt
+		u.LastLoginAt = &t
+	}
+	if u.CreatedBy != nil {
+		id := *u.CreatedBy
+		u.CreatedBy = &id
+	}
+	return &u
+}
+
+// checkUnique 模拟 username、email 的唯一约束，忽略 user 自身
+// 已删除的用户同样占用唯一值，与数据库约束一致
+func (r *memoryUserRepository) checkUnique(user *models.User) error {
+	for id, u := range r.users {
+		if id == user.ID {
+			continue
+		}
+		switch {
+		case u.Username == user.Username:
+			return memoryUniqueViolation("username")
+		case u.Email == user.Email:
+			return memoryUniqueViolation("email")
+		}
+	}
+	return nil
+}
+
+func memoryUniqueViolation(column string) error {
+	return &ConstraintViolation{
+		Kind:       database.ConstraintUnique,
+		Constraint: "users_" + column + "_key",
+		Field:      column,
+		Err:        userUniqueColumns[column],
+	}
+}
+
+// memoryTxManager 直接执行 fn，不提供隔离和回滚
+// 仅用于搭配内存仓储，依赖回滚语义的测试应使用 SQLite
+type memoryTxManager struct{}
+
+// NewMemoryTxManager 创建内存事务管理器，供测试使用
+func NewMemoryTxManager() TxManager {
+	return memoryTxManager{}
+}
+
+func (memoryTxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error, _ ...TxOption) error {
+	return fn(ctx)
+}
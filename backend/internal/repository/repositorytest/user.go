@@ -0,0 +1,254 @@
+// Package repositorytest 仓储接口的契约测试
+// GORM 实现和内存实现运行同一套用例，保证内存实现可以替代真实数据库
+package repositorytest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"artisan-coder/internal/models"
+	"artisan-coder/internal/repository"
+)
+
+// UserRepository 对 newRepo 返回的实现运行 UserRepository 契约
+// newRepo 每次调用需返回一个空仓储
+func UserRepository(t *testing.T, newRepo func(t *testing.T) repository.UserRepository) {
+	t.Run("CreateAssignsIDAndDefaults", func(t *testing.T) {
+		repo := newRepo(t)
+		user := newUser("alice")
+		mustCreate(t, repo, user)
+
+		if user.ID == uuid.Nil {
+			t.Fatal("Create did not assign an ID")
+		}
+		if user.Role != models.RoleUser {
+			t.Errorf("Role = %q, want %q", user.Role, models.RoleUser)
+		}
+		if user.CreatedAt.IsZero() || user.UpdatedAt.IsZero() {
+			t.Error("Create did not set timestamps")
+		}
+	})
+
+	t.Run("FindByIDEmailUsername", func(t *testing.T) {
+		repo := newRepo(t)
+		user := newUser("bob")
+		mustCreate(t, repo, user)
+		ctx := context.Background()
+
+		lookups := map[string]func() (*models.User, error){
+			"FindByID":       func() (*models.User, error) { return repo.FindByID(ctx, user.ID) },
+			"FindByEmail":    func() (*models.User, error) { return repo.FindByEmail(ctx, user.Email) },
+			"FindByUsername": func() (*models.User, error) { return repo.FindByUsername(ctx, user.Username) },
+		}
+		for name, find := range lookups {
+			got, err := find()
+			if err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+			if got.ID != user.ID || got.Email != user.Email || got.Username != user.Username || got.PasswordHash != user.PasswordHash {
+				t.Errorf("%s = %+v, want %+v", name, got, user)
+			}
+		}
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+
+		if _, err := repo.FindByID(ctx, uuid.New()); !errors.Is(err, repository.ErrUserNotFound) {
+			t.Errorf("FindByID error = %v, want ErrUserNotFound", err)
+		}
+		if _, err := repo.FindByEmail(ctx, "missing@example.com"); !errors.Is(err, repository.ErrUserNotFound) {
+			t.Errorf("FindByEmail error = %v, want ErrUserNotFound", err)
+		}
+		if _, err := repo.FindByUsername(ctx, "missing"); !errors.Is(err, repository.ErrUserNotFound) {
+			t.Errorf("FindByUsername error = %v, want ErrUserNotFound", err)
+		}
+	})
+
+	t.Run("CreateDuplicate", func(t *testing.T) {
+		repo := newRepo(t)
+		mustCreate(t, repo, newUser("carol"))
+
+		sameEmail := newUser("carol2")
+		sameEmail.Email = "carol@example.com"
+		assertConflict(t, repo.Create(context.Background(), sameEmail), repository.ErrEmailTaken, "email")
+
+		sameUsername := newUser("carol")
+		sameUsername.Email = "other@example.com"
+		assertConflict(t, repo.Create(context.Background(), sameUsername), repository.ErrUsernameTaken, "username")
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+		user := newUser("dave")
+		mustCreate(t, repo, user)
+
+		user.Role = models.RoleAdmin
+		user.PasswordHash = "changed"
+		if err := repo.Update(ctx, user); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+
+		got, err := repo.FindByID(ctx, user.ID)
+		if err != nil {
+			t.Fatalf("FindByID: %v", err)
+		}
+		if got.Role != models.RoleAdmin || got.PasswordHash != "changed" {
+			t.Errorf("Update not persisted: %+v", got)
+		}
+	})
+
+	t.Run("UpdateDuplicate", func(t *testing.T) {
+		repo := newRepo(t)
+		mustCreate(t, repo, newUser("erin"))
+		user := newUser("frank")
+		mustCreate(t, repo, user)
+
+		user.Email = "erin@example.com"
+		assertConflict(t, repo.Update(context.Background(), user), repository.ErrEmailTaken, "email")
+	})
+
+	t.Run("Disable", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+		user := newUser("heidi")
+		mustCreate(t, repo, user)
+
+		at := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+		user.DisabledAt = &at
+		if err := repo.Update(ctx, user); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+
+		got, err := repo.FindByID(ctx, user.ID)
+		if err != nil {
+			t.Fatalf("FindByID: %v", err)
+		}
+		if !got.Disabled() || !got.DisabledAt.Equal(at) {
+			t.Errorf("DisabledAt = %v, want %v", got.DisabledAt, at)
+		}
+	})
+
+	t.Run("SoftDelete", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+		user := newUser("ivan")
+		mustCreate(t, repo, user)
+
+		if err := repo.Delete(ctx, user.ID); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		if _, err := repo.FindByID(ctx, user.ID); !errors.Is(err, repository.ErrUserNotFound) {
+			t.Errorf("FindByID after delete: error = %v, want ErrUserNotFound", err)
+		}
+		if _, err := repo.FindByEmail(ctx, user.Email); !errors.Is(err, repository.ErrUserNotFound) {
+			t.Errorf("FindByEmail after delete: error = %v, want ErrUserNotFound", err)
+		}
+		if _, err := repo.FindByUsername(ctx, user.Username); !errors.Is(err, repository.ErrUserNotFound) {
+			t.Errorf("FindByUsername after delete: error = %v, want ErrUserNotFound", err)
+		}
+		if err := repo.Delete(ctx, user.ID); !errors.Is(err, repository.ErrUserNotFound) {
+			t.Errorf("second Delete: error = %v, want ErrUserNotFound", err)
+		}
+		if err := repo.RecordLogin(ctx, user.ID, time.Now()); !errors.Is(err, repository.ErrUserNotFound) {
+			t.Errorf("RecordLogin after delete: error = %v, want ErrUserNotFound", err)
+		}
+
+		// 已删除用户的邮箱和用户名仍被占用
+		sameUsername := newUser("ivan")
+		sameUsername.Email = "ivan2@example.com"
+		assertConflict(t, repo.Create(ctx, sameUsername), repository.ErrUsernameTaken, "username")
+		sameEmail := newUser("ivan2")
+		sameEmail.Email = user.Email
+		assertConflict(t, repo.Create(ctx, sameEmail), repository.ErrEmailTaken, "email")
+	})
+
+	t.Run("RecordLogin", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+		user := newUser("judy")
+		mustCreate(t, repo, user)
+		created, err := repo.FindByID(ctx, user.ID)
+		if err != nil {
+			t.Fatalf("FindByID: %v", err)
+		}
+
+		at := created.CreatedAt.Add(time.Hour).UTC().Truncate(time.Second)
+		if err := repo.RecordLogin(ctx, user.ID, at); err != nil {
+			t.Fatalf("RecordLogin: %v", err)
+		}
+
+		got, err := repo.FindByID(ctx, user.ID)
+		if err != nil {
+			t.Fatalf("FindByID: %v", err)
+		}
+		if got.LastLoginAt == nil || !got.LastLoginAt.Equal(at) {
+			t.Errorf("LastLoginAt = %v, want %v", got.LastLoginAt, at)
+		}
+		if !got.UpdatedAt.Equal(created.UpdatedAt) {
+			t.Errorf("RecordLogin changed UpdatedAt from %v to %v", created.UpdatedAt, got.UpdatedAt)
+		}
+		if err := repo.RecordLogin(ctx, uuid.New(), at); !errors.Is(err, repository.ErrUserNotFound) {
+			t.Errorf("RecordLogin for missing user: error = %v, want ErrUserNotFound", err)
+		}
+	})
+
+	t.Run("ReturnsCopies", func(t *testing.T) {
+		repo := newRepo(t)
+		ctx := context.Background()
+		user := newUser("grace")
+		mustCreate(t, repo, user)
+
+		got, err := repo.FindByID(ctx, user.ID)
+		if err != nil {
+			t.Fatalf("FindByID: %v", err)
+		}
+		got.Username = "mutated"
+
+		again, err := repo.FindByID(ctx, user.ID)
+		if err != nil {
+			t.Fatalf("FindByID: %v", err)
+		}
+		if again.Username != "grace" {
+			t.Errorf("modifying a returned user changed the stored one: %q", again.Username)
+		}
+	})
+}
+
+func newUser(username string) *models.User {
+	return &models.User{
+		Username:     username,
+		Email:        username + "@example.com",
+		PasswordHash: "hash-" + username,
+	}
+}
+
+func mustCreate(t *testing.T, repo repository.UserRepository, user *models.User) {
+	t.Helper()
+	if err := repo.Create(context.Background(), user); err != nil {
+		t.Fatalf("Create(%s): %v", user.Username, err)
+	}
+}
+
+func assertConflict(t *testing.T, err, want error, field string) {
+	t.Helper()
+	if !errors.Is(err, want) {
+		t.Fatalf("error = %v, want %v", err, want)
+	}
+	if !errors.Is(err, repository.ErrUserAlreadyExists) {
+		t.Errorf("error %v does not match ErrUserAlreadyExists", err)
+	}
+	var cv *repository.ConstraintViolation
+	if !errors.As(err, &cv) {
+		t.Fatalf("error %v is not a *ConstraintViolation", err)
+	}
+	if cv.Field != field {
+		t.Errorf("Field = %q, want %q", cv.Field, field)
+	}
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+const (
+	// maxTxAttempts 序列化失败时事务的最大执行次数
+	maxTxAttempts = 3
+	// txRetryBackoff 重试前的基础等待时间，按次数递增并加随机抖动
+	txRetryBackoff = 10 * time.Millisecond
+)
+
+// 可重试的 Postgres 错误码
+const (
+	pgSerializationFailure = "40001"
+	pgDeadlockDetected     = "40P01"
+)
+
+type (
+	txKey      struct{}
+	primaryKey struct{}
+)
+
+// TxManager 跨仓储的事务边界
+type TxManager interface {
+	// WithinTx 在事务中执行 fn，fn 返回错误时回滚
+	// 仓储方法使用 fn 收到的 ctx 即自动加入事务；嵌套调用使用 SAVEPOINT
+	// 最外层事务遇到序列化失败或死锁时会重新执行 fn，fn 需可重入
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error, opts ...TxOption) error
+}
+
+// TxOption 事务选项，仅对最外层事务生效
+type TxOption func(*sql.TxOptions)
+
+// WithIsolation 设置事务隔离级别
+func WithIsolation(level sql.IsolationLevel) TxOption {
+	return func(o *sql.TxOptions) {
+		o.Isolation = level
+	}
+}
+
+// ReadOnly 只读事务
+func ReadOnly() TxOption {
+	return func(o *sql.TxOptions) {
+		o.ReadOnly = true
+	}
+}
+
+type txManager struct {
+	db *gorm.DB
+}
+
+func NewTxManager(db *gorm.DB) TxManager {
+	return &txManager{db: db}
+}
+
+func (m *txManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error, opts ...TxOption) error {
+	// 已在事务中：GORM 的嵌套 Transaction 使用 SAVEPOINT，失败只回滚到保存点
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.Transaction(func(nested *gorm.DB) error {
+			return fn(context.WithValue(ctx, txKey{}, nested))
+		})
+	}
+
+	var txOpts sql.TxOptions
+	for _, opt := range opts {
+		opt(&txOpts)
+	}
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			return fn(context.WithValue(ctx, txKey{}, tx))
+		}, &txOpts)
+		if err == nil || attempt >= maxTxAttempts || !retryable(err) {
+			return err
+		}
+
+		wait := txRetryBackoff*time.Duration(attempt) + rand.N(txRetryBackoff)
+		select {
+		case <-ctx.Done():
+			return errors.Join(err, ctx.Err())
+		case <-time.After(wait):
+		}
+	}
+}
+
+// WithPrimary 让 ctx 中的查询读主库，不路由到只读副本
+// 读取结果会被缓存时使用，避免缓存副本上尚未同步的旧数据
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+// conn 返回 ctx 中的事务，不在事务中时返回带 ctx 的 db
+// 仓储方法统一通过它访问数据库
+func conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	db = db.WithContext(ctx)
+	if primary, _ := ctx.Value(primaryKey{}).(bool); primary {
+		db = db.Clauses(dbresolver.Write)
+	}
+	return db
+}
+
+// inTx ctx 是否处于 WithinTx 开启的事务中
+func inTx(ctx context.Context) bool {
+	_, ok := ctx.Value(txKey{}).(*gorm.DB)
+	return ok
+}
+
+// retryable 是否为可重试的并发冲突
+func retryable(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == pgSerializationFailure || pgErr.Code == pgDeadlockDetected
+	}
+	return false
+}
@@ -0,0 +1,237 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+
+	"artisan-coder/internal/database"
+	"artisan-coder/internal/database/databasetest"
+	"artisan-coder/internal/models"
+)
+
+var errRollback = errors.New("rollback")
+
+func TestWithinTx(t *testing.T) {
+	// 每个用例创建 outer、inner 两个用户，want 为提交后仍存在的用户
+	tests := []struct {
+		name  string
+		inner error // inner 所在的嵌套事务返回的错误
+		outer func(innerErr error) error
+		want  []string
+	}{
+		{
+			name:  "commit",
+			outer: func(error) error { return nil },
+			want:  []string{"outer", "inner"},
+		},
+		{
+			name:  "outer rollback after inner commit",
+			outer: func(error) error { return errRollback },
+		},
+		{
+			name:  "inner rollback to savepoint",
+			inner: errRollback,
+			outer: func(error) error { return nil },
+			want:  []string{"outer"},
+		},
+		{
+			name:  "inner error propagated",
+			inner: errRollback,
+			outer: func(innerErr error) error { return innerErr },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := databasetest.Open(t, database.DriverSQLite)
+			txm := NewTxManager(db)
+			repo := NewUserRepository(db)
+			ctx := context.Background()
+
+			err := txm.WithinTx(ctx, func(ctx context.Context) error {
+				if err := repo.Create(ctx, newTxUser("outer")); err != nil {
+					return err
+				}
+				innerErr := txm.WithinTx(ctx, func(ctx context.Context) error {
+					if err := repo.Create(ctx, newTxUser("inner")); err != nil {
+						return err
+					}
+					return tt.inner
+				})
+				if !errors.Is(innerErr, tt.inner) {
+					t.Errorf("inner WithinTx = %v, want %v", innerErr, tt.inner)
+				}
+				return tt.outer(innerErr)
+			})
+			if wantErr := tt.outer(tt.inner); !errors.Is(err, wantErr) {
+				t.Errorf("WithinTx = %v, want %v", err, wantErr)
+			}
+
+			for _, name := range []string{"outer", "inner"} {
+				_, err := repo.FindByUsername(ctx, name)
+				exists := err == nil
+				if err != nil && !errors.Is(err, ErrUserNotFound) {
+					t.Fatal(err)
+				}
+				if want := slices.Contains(tt.want, name); exists != want {
+					t.Errorf("user %q exists = %t, want %t", name, exists, want)
+				}
+			}
+		})
+	}
+}
+
+func TestWithinTxRetry(t *testing.T) {
+	serialization := &pgconn.PgError{Code: pgSerializationFailure}
+	unique := &pgconn.PgError{Code: "23505"}
+	tests := []struct {
+		name     string
+		errs     []error // 每次执行 fn 返回的错误，超出部分返回 nil
+		wantErr  error
+		attempts int
+	}{
+		{name: "success", attempts: 1},
+		{name: "retry then commit", errs: []error{serialization}, attempts: 2},
+		{name: "deadlock", errs: []error{&pgconn.PgError{Code: pgDeadlockDetected}}, attempts: 2},
+		{
+			name:     "give up",
+			errs:     []error{serialization, serialization, serialization, serialization},
+			wantErr:  serialization,
+			attempts: maxTxAttempts,
+		},
+		{name: "not retryable", errs: []error{errRollback}, wantErr: errRollback, attempts: 1},
+		{name: "unique violation", errs: []error{unique}, wantErr: unique, attempts: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			txm := NewTxManager(databasetest.Open(t, database.DriverSQLite))
+
+			attempts := 0
+			err := txm.WithinTx(context.Background(), func(ctx context.Context) error {
+				attempts++
+				if attempts <= len(tt.errs) {
+					return fmt.Errorf("query: %w", tt.errs[attempts-1])
+				}
+				return nil
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("WithinTx = %v, want %v", err, tt.wantErr)
+			}
+			if attempts != tt.attempts {
+				t.Errorf("attempts = %d, want %d", attempts, tt.attempts)
+			}
+		})
+	}
+}
+
+// TestWithinTxNestedNoRetry 嵌套事务不单独重试，由最外层重新执行整个 fn
+func TestWithinTxNestedNoRetry(t *testing.T) {
+	txm := NewTxManager(databasetest.Open(t, database.DriverSQLite))
+
+	outer, inner := 0, 0
+	err := txm.WithinTx(context.Background(), func(ctx context.Context) error {
+		outer++
+		return txm.WithinTx(ctx, func(ctx context.Context) error {
+			inner++
+			return &pgconn.PgError{Code: pgSerializationFailure}
+		})
+	})
+	if !retryable(err) {
+		t.Errorf("WithinTx = %v, want the serialization failure", err)
+	}
+	if outer != maxTxAttempts || inner != maxTxAttempts {
+		t.Errorf("outer = %d, inner = %d attempts, want %d each", outer, inner, maxTxAttempts)
+	}
+}
+
+// recordingPool 记录开启事务时的选项
+type recordingPool struct {
+	*sql.DB
+	opts []sql.TxOptions
+}
+
+func (p *recordingPool) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	p.opts = append(p.opts, *opts)
+	return p.DB.BeginTx(ctx, opts)
+}
+
+func TestWithinTxOptions(t *testing.T) {
+	sqlDB, err := databasetest.Open(t, database.DriverSQLite).DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool := &recordingPool{DB: sqlDB}
+	db, err := gorm.Open(&sqlite.Dialector{Conn: pool}, &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	txm := NewTxManager(db)
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		opts []TxOption
+		want sql.TxOptions
+	}{
+		{name: "default"},
+		{name: "isolation", opts: []TxOption{WithIsolation(sql.LevelSerializable)}, want: sql.TxOptions{Isolation: sql.LevelSerializable}},
+		{name: "read only", opts: []TxOption{ReadOnly()}, want: sql.TxOptions{ReadOnly: true}},
+		{
+			name: "combined",
+			opts: []TxOption{ReadOnly(), WithIsolation(sql.LevelRepeatableRead)},
+			want: sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool.opts = nil
+			err := txm.WithinTx(ctx, func(ctx context.Context) error {
+				// 嵌套事务使用保存点，选项被忽略
+				return txm.WithinTx(ctx, func(context.Context) error { return nil }, ReadOnly())
+			}, tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(pool.opts) != 1 || pool.opts[0] != tt.want {
+				t.Errorf("BeginTx options = %+v, want [%+v]", pool.opts, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil"},
+		{name: "plain", err: errRollback},
+		{name: "serialization failure", err: &pgconn.PgError{Code: pgSerializationFailure}, want: true},
+		{name: "deadlock", err: &pgconn.PgError{Code: pgDeadlockDetected}, want: true},
+		{name: "wrapped", err: fmt.Errorf("update users: %w", &pgconn.PgError{Code: pgSerializationFailure}), want: true},
+		{name: "joined", err: errors.Join(errRollback, &pgconn.PgError{Code: pgDeadlockDetected}), want: true},
+		{name: "unique violation", err: &pgconn.PgError{Code: "23505"}},
+		{name: "constraint violation", err: &ConstraintViolation{Err: ErrUserAlreadyExists, cause: &pgconn.PgError{Code: "23505"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := retryable(tt.err); got != tt.want {
+				t.Errorf("retryable(%v) = %t, want %t", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func newTxUser(name string) *models.User {
+	return &models.User{Username: name, Email: name + "@example.com", PasswordHash: "hash"}
+}
@@ -3,6 +3,8 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"go.uber.org/fx"
@@ -12,16 +14,31 @@ import (
 )
 
 var (
-	ErrUserNotFound    = errors.New("user not found")
+	ErrUserNotFound      = errors.New("user not found")
 	ErrUserAlreadyExists = errors.New("user already exists")
+
+	// 具体冲突字段，errors.Is 同样匹配 ErrUserAlreadyExists
+	ErrUsernameTaken = fmt.Errorf("username taken: %w", ErrUserAlreadyExists)
+	ErrEmailTaken    = fmt.Errorf("email taken: %w", ErrUserAlreadyExists)
 )
 
+// userUniqueColumns users 表唯一约束列对应的领域错误
+var userUniqueColumns = map[string]error{
+	"username": ErrUsernameTaken,
+	"email":    ErrEmailTaken,
+}
+
+// UserRepository 用户仓储，FindBy* 不返回已软删除的用户
 type UserRepository interface {
 	Create(ctx context.Context, user *models.User) error
 	FindByEmail(ctx context.Context, email string) (*models.User, error)
 	FindByID(ctx context.Context, id uuid.UUID) (*models.User, error)
 	FindByUsername(ctx context.Context, username string) (*models.User, error)
 	Update(ctx context.Context, user *models.User) error
+	// Delete 软删除用户，用户不存在或已删除时返回 ErrUserNotFound
+	Delete(ctx context.Context, id uuid.UUID) error
+	// RecordLogin 记录最后登录时间，不改变 updated_at
+	RecordLogin(ctx context.Context, id uuid.UUID, at time.Time) error
 }
 
 type userRepository struct {
@@ -33,20 +50,17 @@ func NewUserRepository(db *gorm.DB) UserRepository {
 }
 
 func (r *userRepository) Create(ctx context.Context, user *models.User) error {
-	result := r.db.WithContext(ctx).Create(user)
+	result := conn(ctx, r.db).Create(user)
 	if result.Error != nil {
-		// 检查是否是唯一约束冲突
-		if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
-			return ErrUserAlreadyExists
-		}
-		return result.Error
+		// 唯一约束冲突映射为具体字段的领域错误
+		return mapConstraintError(result.Error, userUniqueColumns, ErrUserAlreadyExists)
 	}
 	return nil
 }
 
 func (r *userRepository) FindByEmail(ctx context.Context, email string) (*models.User, error) {
 	var user models.User
-	result := r.db.WithContext(ctx).Where("email = ?", email).First(&user)
+	result := conn(ctx, r.db).Where("email = ?", email).First(&user)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, ErrUserNotFound
@@ -58,7 +72,7 @@ func (r *userRepository) FindByEmail(ctx context.Context, email string) (*models
 
 func (r *userRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
 	var user models.User
-	result := r.db.WithContext(ctx).Where("id = ?", id).First(&user)
+	result := conn(ctx, r.db).Where("id = ?", id).First(&user)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, ErrUserNotFound
@@ -70,7 +84,7 @@ func (r *userRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Us
 
 func (r *userRepository) FindByUsername(ctx context.Context, username string) (*models.User, error) {
 	var user models.User
-	result := r.db.WithContext(ctx).Where("username = ?", username).First(&user)
+	result := conn(ctx, r.db).Where("username = ?", username).First(&user)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, ErrUserNotFound
@@ -81,13 +95,39 @@ func (r *userRepository) FindByUsername(ctx context.Context, username string) (*
 }
 
 func (r *userRepository) Update(ctx context.Context, user *models.User) error {
-	result := r.db.WithContext(ctx).Save(user)
-	return result.Error
+	result := conn(ctx, r.db).Save(user)
+	if result.Error != nil {
+		return mapConstraintError(result.Error, userUniqueColumns, ErrUserAlreadyExists)
+	}
+	return nil
+}
+
+func (r *userRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result := conn(ctx, r.db).Where("id = ?", id).Delete(&models.User{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *userRepository) RecordLogin(ctx context.Context, id uuid.UUID, at time.Time) error {
+	result := conn(ctx, r.db).Model(&models.User{}).Where("id = ?", id).UpdateColumn("last_login_at", at)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrUserNotFound
+	}
+	return nil
 }
 
 // Module 返回 Repository 模块的 FX 选项
 func Module() fx.Option {
-	return fx.Provide(
-		NewUserRepository,
+	return fx.Options(
+		fx.Provide(NewUserRepository, NewTxManager),
+		fx.Decorate(decorateUserRepository),
 	)
 }
@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/fx"
+	"gorm.io/gorm"
+
+	"artisan-coder/internal/cache"
+	"artisan-coder/internal/config"
+	"artisan-coder/internal/database"
+	"artisan-coder/internal/metrics"
+	"artisan-coder/internal/models"
+	"artisan-coder/pkg/clock"
+)
+
+// userCacheChannel 用户缓存失效通知的 Postgres channel，payload 为用户 ID
+const userCacheChannel = "user_cache_invalidate"
+
+// cachedUserRepository 缓存 FindByID 的结果
+// 本实例的 Update、Delete 直接失效缓存；开启通知时再通过 pg_notify 通知其他实例
+// RecordLogin 只原地更新本实例缓存中的 last_login_at，其他实例最迟在 ttl 后可见
+// 事务内的查询绕过缓存，避免缓存未提交的数据；未命中时读主库，见 WithPrimary
+type cachedUserRepository struct {
+	next   UserRepository
+	cache  cache.Cache[uuid.UUID, models.User]
+	notify func(ctx context.Context, id uuid.UUID) error // 未开启通知时为 nil
+
+	hits   prometheus.Counter // 未加载指标模块时为 nil
+	misses prometheus.Counter
+}
+
+// NewCachedUserRepository 为 UserRepository 增加按 ID 查询的缓存
+// notify 用于通知其他实例失效，可为 nil
+func NewCachedUserRepository(next UserRepository, c cache.Cache[uuid.UUID, models.User], notify func(ctx context.Context, id uuid.UUID) error, m *metrics.Metrics) UserRepository {
+	r := &cachedUserRepository{
+		next:   next,
+		cache:  c,
+		notify: notify,
+	}
+	// 运维命令不加载指标模块
+	if m != nil {
+		r.hits = m.CacheRequests.WithLabelValues("users", metrics.CacheHit)
+		r.misses = m.CacheRequests.WithLabelValues("users", metrics.CacheMiss)
+	}
+	return r
+}
+
+func (r *cachedUserRepository) Create(ctx context.Context, user *models.User) error {
+	return r.next.Create(ctx, user)
+}
+
+func (r *cachedUserRepository) FindByEmail(ctx context.Context, email string) (*models.User, error) {
+	return r.next.FindByEmail(ctx, email)
+}
+
+func (r *cachedUserRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	if inTx(ctx) {
+		return r.next.FindByID(ctx, id)
+	}
+
+	if user, ok := r.cache.Get(id); ok {
+		inc(r.hits)
+		return cloneUser(user), nil
+	}
+	inc(r.misses)
+
+	// 失效后从只读副本重新加载可能拿到旧数据并缓存 ttl，缓存只从主库填充
+	user, err := r.next.FindByID(WithPrimary(ctx), id)
+	if err != nil {
+		return nil, err
+	}
+	r.cache.Set(id, *cloneUser(*user))
+	return user, nil
+}
+
+func (r *cachedUserRepository) FindByUsername(ctx context.Context, username string) (*models.User, error) {
+	return r.next.FindByUsername(ctx, username)
+}
+
+func (r *cachedUserRepository) Update(ctx context.Context, user *models.User) error {
+	if err := r.next.Update(ctx, user); err != nil {
+		return err
+	}
+	return r.invalidate(ctx, user.ID)
+}
+
+func (r *cachedUserRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := r.next.Delete(ctx, id); err != nil {
+		return err
+	}
+	return r.invalidate(ctx, id)
+}
+
+// RecordLogin 不失效缓存，也不通知其他实例，避免每次登录都清掉活跃用户的缓存
+func (r *cachedUserRepository) RecordLogin(ctx context.Context, id uuid.UUID, at time.Time) error {
+	if err := r.next.RecordLogin(ctx, id, at); err != nil {
+		return err
+	}
+	r.cache.Update(id, func(user models.User) models.User {
+		user.LastLoginAt = &at
+		return user
+	})
+	return nil
+}
+
+// invalidate 失效本实例缓存并通知其他实例
+// 在事务中时通知随提交送达；本实例缓存立即失效，事务期间其他请求仍可能读到旧值并重新缓存，最长保留 ttl
+func (r *cachedUserRepository) invalidate(ctx context.Context, id uuid.UUID) error {
+	r.cache.Delete(id)
+	if r.notify == nil {
+		return nil
+	}
+	return r.notify(ctx, id)
+}
+
+func inc(c prometheus.Counter) {
+	if c != nil {
+		c.Inc()
+	}
+}
+
+// userRepositoryDecoration 装饰 UserRepository 所需的依赖
+type userRepositoryDecoration struct {
+	fx.In
+	Next      UserRepository
+	Lifecycle fx.Lifecycle
+	Config    *config.Config
+	DB        *gorm.DB
+	Clock     clock.Clock
+	Tracer    trace.TracerProvider
+	Logger    *slog.Logger
+	Metrics   *metrics.Metrics `optional:"true"`
+}
+
+// decorateUserRepository 依次叠加缓存和追踪装饰器
+// 同一类型在模块内只能注册一个 fx.Decorate
+func decorateUserRepository(in userRepositoryDecoration) UserRepository {
+	next := in.Next
+	if in.Config.UserCache.Enabled {
+		next = newUserCache(in, next)
+	}
+	return NewTracedUserRepository(next, in.Tracer)
+}
+
+// newUserCache 创建缓存装饰器，开启通知时注册监听其他实例的失效通知
+func newUserCache(in userRepositoryDecoration, next UserRepository) UserRepository {
+	cfg := in.Config.UserCache
+	c := cache.NewLRU[uuid.UUID, models.User](cfg.Size, cfg.TTL, in.Clock)
+	if !cfg.Notify {
+		return NewCachedUserRepository(next, c, nil, in.Metrics)
+	}
+
+	notify := func(ctx context.Context, id uuid.UUID) error {
+		return database.Notify(conn(ctx, in.DB), userCacheChannel, id.String())
+	}
+
+	log := in.Logger.With("component", "user_cache")
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	in.Lifecycle.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+				database.Listen(ctx, in.Config.Database, userCacheChannel, log,
+					// 断线期间可能错过通知
+					c.Purge,
+					func(payload string) {
+						id, err := uuid.Parse(payload)
+						if err != nil {
+							log.Warn("Invalid user cache notification", "payload", payload)
+							return
+						}
+						c.Delete(id)
+					})
+			}()
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+			case <-stopCtx.Done():
+			}
+			return nil
+		},
+	})
+
+	return NewCachedUserRepository(next, c, notify, in.Metrics)
+}
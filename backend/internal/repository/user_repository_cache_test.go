@@ -0,0 +1,138 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+
+	"artisan-coder/internal/cache"
+	"artisan-coder/internal/database"
+	"artisan-coder/internal/database/databasetest"
+	"artisan-coder/internal/models"
+	"artisan-coder/internal/repository"
+	"artisan-coder/internal/repository/repositorytest"
+	"artisan-coder/pkg/clock"
+	"artisan-coder/pkg/idgen"
+)
+
+// countingRepository 统计落到下层仓储的 FindByID 次数
+type countingRepository struct {
+	repository.UserRepository
+	finds int
+}
+
+func (r *countingRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	r.finds++
+	return r.UserRepository.FindByID(ctx, id)
+}
+
+func newCachedRepository(clk clock.Clock) (repository.UserRepository, *countingRepository) {
+	next := &countingRepository{UserRepository: repository.NewMemoryUserRepository(clk, idgen.New())}
+	c := cache.NewLRU[uuid.UUID, models.User](100, time.Minute, clk)
+	return repository.NewCachedUserRepository(next, c, nil, nil), next
+}
+
+func TestCachedUserRepository(t *testing.T) {
+	repositorytest.UserRepository(t, func(t *testing.T) repository.UserRepository {
+		repo, _ := newCachedRepository(clock.New())
+		return repo
+	})
+}
+
+func TestCachedUserRepositoryRecordLogin(t *testing.T) {
+	clk := clock.NewFake(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC))
+	repo, next := newCachedRepository(clk)
+	ctx := context.Background()
+
+	user := &models.User{Username: "alice", Email: "alice@example.com", PasswordHash: "hash"}
+	if err := repo.Create(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.FindByID(ctx, user.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	at := clk.Now().Add(time.Second)
+	if err := repo.RecordLogin(ctx, user.ID, at); err != nil {
+		t.Fatal(err)
+	}
+	got, err := repo.FindByID(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next.finds != 1 {
+		t.Errorf("RecordLogin invalidated the cache: %d lookups reached the repository", next.finds)
+	}
+	if got.LastLoginAt == nil || !got.LastLoginAt.Equal(at) {
+		t.Errorf("LastLoginAt = %v, want %v", got.LastLoginAt, at)
+	}
+
+	// Update 失效缓存
+	user.DisabledAt = &at
+	if err := repo.Update(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+	got, err = repo.FindByID(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next.finds != 2 || !got.Disabled() {
+		t.Errorf("Update did not invalidate the cache: finds = %d, disabled = %t", next.finds, got.Disabled())
+	}
+}
+
+// TestCachedUserRepositoryFillsFromPrimary 失效后重新加载读主库，不缓存只读副本上的旧数据
+// 副本是另一个 SQLite 库，停留在禁用之前
+func TestCachedUserRepositoryFillsFromPrimary(t *testing.T) {
+	ctx := context.Background()
+	primary := databasetest.Open(t, database.DriverSQLite)
+	replica := databasetest.Open(t, database.DriverSQLite)
+	replicaPool, err := replica.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := primary.Use(dbresolver.Register(dbresolver.Config{
+		Replicas: []gorm.Dialector{&sqlite.Dialector{Conn: replicaPool}},
+	}, &models.User{})); err != nil {
+		t.Fatal(err)
+	}
+
+	next := repository.NewUserRepository(primary)
+	c := cache.NewLRU[uuid.UUID, models.User](100, time.Minute, clock.New())
+	repo := repository.NewCachedUserRepository(next, c, nil, nil)
+
+	user := &models.User{Username: "alice", Email: "alice@example.com", PasswordHash: "hash"}
+	if err := repo.Create(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+	stale := *user
+	if err := replica.Create(&stale).Error; err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.FindByID(ctx, user.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	user.DisabledAt = &now
+	if err := repo.Update(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+
+	// 不经过缓存的查询仍路由到副本，读到的是旧数据
+	if got, err := next.FindByID(ctx, user.ID); err != nil || got.Disabled() {
+		t.Fatalf("replica read = %+v, %v; want the stale enabled user", got, err)
+	}
+	got, err := repo.FindByID(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Disabled() {
+		t.Error("cache was refilled from the stale replica")
+	}
+}
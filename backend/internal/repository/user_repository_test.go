@@ -0,0 +1,30 @@
+package repository_test
+
+import (
+	"testing"
+
+	"artisan-coder/internal/database/databasetest"
+	"artisan-coder/internal/repository"
+	"artisan-coder/internal/repository/repositorytest"
+	"artisan-coder/pkg/clock"
+	"artisan-coder/pkg/idgen"
+)
+
+// TestUserRepository 在每种数据库驱动上运行同一套契约
+// Postgres 需设置 databasetest.PostgresURLEnv
+func TestUserRepository(t *testing.T) {
+	for _, driver := range databasetest.Drivers {
+		t.Run(driver, func(t *testing.T) {
+			databasetest.SkipUnavailable(t, driver)
+			repositorytest.UserRepository(t, func(t *testing.T) repository.UserRepository {
+				return repository.NewUserRepository(databasetest.Open(t, driver))
+			})
+		})
+	}
+}
+
+func TestMemoryUserRepository(t *testing.T) {
+	repositorytest.UserRepository(t, func(t *testing.T) repository.UserRepository {
+		return repository.NewMemoryUserRepository(clock.New(), idgen.New())
+	})
+}
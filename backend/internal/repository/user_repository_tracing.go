@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"artisan-coder/internal/models"
+)
+
+const tracerName = "artisan-coder/internal/repository"
+
+// tracedUserRepository 为每次仓储调用创建子 span
+type tracedUserRepository struct {
+	next   UserRepository
+	tracer trace.Tracer
+}
+
+// NewTracedUserRepository 为 UserRepository 增加链路追踪
+func NewTracedUserRepository(next UserRepository, tp trace.TracerProvider) UserRepository {
+	return &tracedUserRepository{
+		next:   next,
+		tracer: tp.Tracer(tracerName),
+	}
+}
+
+func (r *tracedUserRepository) Create(ctx context.Context, user *models.User) error {
+	ctx, span := r.tracer.Start(ctx, "UserRepository.Create")
+	defer span.End()
+
+	return record(span, r.next.Create(ctx, user))
+}
+
+func (r *tracedUserRepository) FindByEmail(ctx context.Context, email string) (*models.User, error) {
+	ctx, span := r.tracer.Start(ctx, "UserRepository.FindByEmail")
+	defer span.End()
+
+	user, err := r.next.FindByEmail(ctx, email)
+	return user, record(span, err)
+}
+
+func (r *tracedUserRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	ctx, span := r.tracer.Start(ctx, "UserRepository.FindByID",
+		trace.WithAttributes(attribute.String("user.id", id.String())))
+	defer span.End()
+
+	user, err := r.next.FindByID(ctx, id)
+	return user, record(span, err)
+}
+
+func (r *tracedUserRepository) FindByUsername(ctx context.Context, username string) (*models.User, error) {
+	ctx, span := r.tracer.Start(ctx, "UserRepository.FindByUsername")
+	defer span.End()
+
+	user, err := r.next.FindByUsername(ctx, username)
+	return user, record(span, err)
+}
+
+func (r *tracedUserRepository) Update(ctx context.Context, user *models.User) error {
+	ctx, span := r.tracer.Start(ctx, "UserRepository.Update",
+		trace.WithAttributes(attribute.String("user.id", user.ID.String())))
+	defer span.End()
+
+	return record(span, r.next.Update(ctx, user))
+}
+
+func (r *tracedUserRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	ctx, span := r.tracer.Start(ctx, "UserRepository.Delete",
+		trace.WithAttributes(attribute.String("user.id", id.String())))
+	defer span.End()
+
+	return record(span, r.next.Delete(ctx, id))
+}
+
+func (r *tracedUserRepository) RecordLogin(ctx context.Context, id uuid.UUID, at time.Time) error {
+	ctx, span := r.tracer.Start(ctx, "UserRepository.RecordLogin",
+		trace.WithAttributes(attribute.String("user.id", id.String())))
+	defer span.End()
+
+	return record(span, r.next.RecordLogin(ctx, id, at))
+}
+
+// record 将错误记录到 span；记录不存在属于正常结果，不标记为错误
+func record(span trace.Span, err error) error {
+	if err != nil && !errors.Is(err, ErrUserNotFound) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
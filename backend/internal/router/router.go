@@ -1,12 +1,19 @@
 package router
 
 import (
-	"go.uber.org/fx"
+	"log/slog"
+
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/fx"
 
 	"artisan-coder/internal/config"
 	"artisan-coder/internal/handler"
+	"artisan-coder/internal/metrics"
 	"artisan-coder/internal/middleware"
+	"artisan-coder/internal/ratelimit"
+	"artisan-coder/internal/service"
 	"artisan-coder/pkg/jwt"
 )
 
@@ -20,9 +27,17 @@ func Module() fx.Option {
 // RouterIn 路由模块的依赖组
 type RouterIn struct {
 	fx.In
-	AuthHandler *handler.AuthHandler
-	JWTManager  *jwt.Manager
-	Config      *config.Config
+	AuthHandler   *handler.AuthHandler
+	HealthHandler *handler.HealthHandler
+	JWTManager    *jwt.Manager
+	UserStatus    *service.UserStatusChecker
+	Config        *config.Config
+	Watcher       *config.Watcher
+	Logger        *slog.Logger
+	RateLimits    *ratelimit.Registry
+	Metrics       *metrics.Metrics
+	MetricsHTTP   metrics.Handler
+	Tracer        trace.TracerProvider
 }
 
 // NewRouter 创建 Gin 路由
@@ -33,29 +48,55 @@ func NewRouter(in RouterIn) *gin.Engine {
 	router := gin.New()
 
 	// 全局中间件
-	router.Use(middleware.CORS(in.Config.CORS.AllowedOrigins))
-	router.Use(middleware.Logger())
-	router.Use(gin.Recovery())
+	router.Use(middleware.RequestID())
+	router.Use(otelgin.Middleware(in.Config.Tracing.ServiceName,
+		otelgin.WithTracerProvider(in.Tracer),
+	))
+	router.Use(middleware.Metrics(in.Metrics))
+	// 先于 CORS 注册，被拒绝的跨域请求和预检请求同样记录日志
+	router.Use(middleware.Logger(in.Logger))
+	router.Use(middleware.Recovery(in.Logger))
+	router.Use(middleware.ReloadableCORS(in.Watcher))
+	router.Use(middleware.Locale())
 
 	// 注册路由
-	setupRoutes(router, in.AuthHandler, in.JWTManager)
+	setupRoutes(router, in)
 
 	return router
 }
 
 // setupRoutes 配置所有路由
-func setupRoutes(router *gin.Engine, authHandler *handler.AuthHandler, jwtManager *jwt.Manager) {
+func setupRoutes(router *gin.Engine, in RouterIn) {
+	authHandler := in.AuthHandler
+	jwtManager := in.JWTManager
+
+	// 健康检查
+	router.GET("/healthz", in.HealthHandler.Liveness)
+	router.GET("/readyz", in.HealthHandler.Readiness)
+
+	// 未配置独立端口时，指标与 API 共用端口
+	if in.Config.Metrics.Enabled && in.Config.Metrics.Port == "" {
+		router.GET(in.Config.Metrics.Path, gin.WrapH(in.MetricsHTTP))
+	}
+
+	// api 组限流在认证之后执行，key 为 user 时才能取到用户 ID
+	apiLimit := middleware.RateLimit(in.RateLimits, "api", in.Logger)
+	authenticated := []gin.HandlerFunc{middleware.Auth(jwtManager, in.UserStatus), apiLimit}
+
 	api := router.Group("/api")
 	{
 		auth := api.Group("/auth")
+		auth.Use(middleware.RateLimit(in.RateLimits, "auth", in.Logger))
 		{
-			auth.POST("/register", authHandler.Register)
-			auth.POST("/login", authHandler.Login)
-			auth.POST("/logout", authHandler.Logout)
-			auth.POST("/refresh", authHandler.RefreshToken)
+			// 公开路由按 IP 限流
+			public := auth.Group("", apiLimit)
+			public.POST("/register", authHandler.Register)
+			public.POST("/login", authHandler.Login)
+			public.POST("/logout", authHandler.Logout)
+			public.POST("/refresh", authHandler.RefreshToken)
 
 			// 需要认证的路由
-			auth.GET("/me", middleware.Auth(jwtManager), authHandler.GetCurrentUser)
+			auth.GET("/me", append(authenticated, authHandler.GetCurrentUser)...)
 		}
 	}
 }
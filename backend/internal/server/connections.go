@@ -0,0 +1,123 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Closer 长连接（流式响应、WebSocket 等）
+// Close 应通知对端并尽快结束连接，ctx 到期后服务器将强制关闭
+type Closer interface {
+	Close(ctx context.Context) error
+}
+
+// CloserFunc 适配函数为 Closer
+type CloserFunc func(ctx context.Context) error
+
+func (f CloserFunc) Close(ctx context.Context) error {
+	return f(ctx)
+}
+
+// Connections 长连接注册表
+// http.Server.Shutdown 不会等待被劫持的连接，也无法通知流式响应结束，
+// 因此由注册表在关闭时统一通知并等待它们退出
+type Connections struct {
+	mu       sync.Mutex
+	nextID   uint64
+	conns    map[uint64]Closer
+	wg       sync.WaitGroup
+	closing  chan struct{}
+	shutdown bool
+}
+
+// NewConnections 创建长连接注册表
+func NewConnections() *Connections {
+	return &Connections{
+		conns:   make(map[uint64]Closer),
+		closing: make(chan struct{}),
+	}
+}
+
+// ErrShuttingDown 服务器关闭中，不再接受新的长连接
+var ErrShuttingDown = errors.New("server is shutting down")
+
+// Register 注册长连接，连接结束时必须调用返回的 done
+func (r *Connections) Register(c Closer) (done func(), err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.shutdown {
+		return nil, ErrShuttingDown
+	}
+
+	id := r.nextID
+	r.nextID++
+	r.conns[id] = c
+	r.wg.Add(1)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			r.mu.Lock()
+			delete(r.conns, id)
+			r.mu.Unlock()
+			r.wg.Done()
+		})
+	}, nil
+}
+
+// Closing 关闭开始时关闭的通道，流式处理器可直接 select
+func (r *Connections) Closing() <-chan struct{} {
+	return r.closing
+}
+
+// Len 当前长连接数
+func (r *Connections) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.conns)
+}
+
+// Shutdown 通知所有长连接关闭，并等待其退出或 ctx 到期
+func (r *Connections) Shutdown(ctx context.Context) error {
+	r.mu.Lock()
+	if !r.shutdown {
+		r.shutdown = true
+		close(r.closing)
+	}
+	conns := make([]Closer, 0, len(r.conns))
+	for _, c := range r.conns {
+		conns = append(conns, c)
+	}
+	r.mu.Unlock()
+
+	var (
+		errMu sync.Mutex
+		errs  []error
+	)
+	for _, c := range conns {
+		go func(c Closer) {
+			if err := c.Close(ctx); err != nil {
+				errMu.Lock()
+				errs = append(errs, err)
+				errMu.Unlock()
+			}
+		}(c)
+	}
+
+	waited := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(waited)
+	}()
+
+	select {
+	case <-waited:
+		errMu.Lock()
+		defer errMu.Unlock()
+		return errors.Join(errs...)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
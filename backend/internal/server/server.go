@@ -2,19 +2,27 @@ package server
 
 import (
 	"context"
-	"log"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
 	"net/http"
+	"time"
 
-	"go.uber.org/fx"
 	"github.com/gin-gonic/gin"
+	"go.uber.org/fx"
 
 	"artisan-coder/internal/config"
+	"artisan-coder/internal/health"
 )
 
 // Module 返回服务器模块的 FX 选项
 func Module() fx.Option {
 	return fx.Options(
-		fx.Provide(NewServer),
+		fx.Provide(
+			NewServer,
+			NewConnections,
+		),
 		fx.Invoke(RegisterHooks),
 	)
 }
@@ -22,38 +30,150 @@ func Module() fx.Option {
 // ServerIn 服务器模块的依赖组
 type ServerIn struct {
 	fx.In
-	Router *gin.Engine
-	Config *config.Config
+	Lifecycle fx.Lifecycle
+	Router    *gin.Engine
+	Config    *config.Config
+	Logger    *slog.Logger
 }
 
 // NewServer 创建 HTTP 服务器
-func NewServer(in ServerIn) *http.Server {
-	return &http.Server{
-		Addr:         ":" + in.Config.Server.Port,
+// 配置证书时启用 TLS（自动协商 HTTP/2），否则可选开启 h2c
+func NewServer(in ServerIn) (*http.Server, error) {
+	cfg := in.Config.Server
+	server := &http.Server{
+		Addr:         ":" + cfg.Port,
 		Handler:      in.Router,
-		ReadTimeout:  in.Config.Server.ReadTimeout,
-		WriteTimeout: in.Config.Server.WriteTimeout,
+		ReadTimeout:  cfg.ReadTimeout,
+		WriteTimeout: cfg.WriteTimeout,
+	}
+
+	if cfg.TLS.Enabled() {
+		reloader, err := newCertReloader(cfg.TLS, in.Logger)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig, err := newTLSConfig(cfg.TLS, reloader)
+		if err != nil {
+			return nil, err
+		}
+		server.TLSConfig = tlsConfig
+
+		ctx, cancel := context.WithCancel(context.Background())
+		in.Lifecycle.Append(fx.Hook{
+			OnStart: func(context.Context) error {
+				return reloader.watch(ctx)
+			},
+			OnStop: func(context.Context) error {
+				cancel()
+				return nil
+			},
+		})
+		return server, nil
+	}
+
+	if cfg.H2C {
+		// 明文 HTTP/2，供反向代理以 h2c 回源
+		var protocols http.Protocols
+		protocols.SetHTTP1(true)
+		protocols.SetUnencryptedHTTP2(true)
+		server.Protocols = &protocols
 	}
+
+	return server, nil
+}
+
+// HooksIn 生命周期钩子的依赖组
+type HooksIn struct {
+	fx.In
+	Lifecycle   fx.Lifecycle
+	Shutdowner  fx.Shutdowner
+	Server      *http.Server
+	Config      *config.Config
+	Health      *health.Health
+	Connections *Connections
+	Logger      *slog.Logger
 }
 
 // RegisterHooks 注册服务器生命周期钩子
-func RegisterHooks(lc fx.Lifecycle, server *http.Server, cfg *config.Config) {
-	lc.Append(fx.Hook{
+// 端口在 OnStart 中同步绑定，失败时直接阻止应用启动；
+// 运行期错误通过 Shutdowner 触发正常的 OnStop 流程
+func RegisterHooks(in HooksIn) {
+	server := in.Server
+	cfg := in.Config.Server
+	log := in.Logger
+
+	in.Lifecycle.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
-			log.Printf("Starting HTTP server on port %s", cfg.Server.Port)
+			ln, err := net.Listen("tcp", server.Addr)
+			if err != nil {
+				return fmt.Errorf("failed to listen on %s: %w", server.Addr, err)
+			}
+
+			log.Info("Starting HTTP server",
+				"addr", ln.Addr().String(),
+				"tls", server.TLSConfig != nil,
+				"h2c", server.TLSConfig == nil && cfg.H2C,
+			)
 
-			// 在 goroutine 中启动服务器
 			go func() {
-				if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-					log.Fatalf("HTTP server failed: %v", err)
+				var err error
+				if server.TLSConfig != nil {
+					// 证书由 TLSConfig.GetCertificate 提供
+					err = server.ServeTLS(ln, "", "")
+				} else {
+					err = server.Serve(ln)
+				}
+				if err != nil && !errors.Is(err, http.ErrServerClosed) {
+					log.Error("HTTP server failed", "error", err)
+					if err := in.Shutdowner.Shutdown(fx.ExitCode(1)); err != nil {
+						log.Error("Failed to trigger shutdown", "error", err)
+					}
 				}
 			}()
 
 			return nil
 		},
 		OnStop: func(ctx context.Context) error {
-			log.Println("Shutting down HTTP server...")
-			return server.Shutdown(ctx)
+			// 先让就绪检查失败，等待负载均衡摘除流量后再关闭
+			in.Health.MarkShuttingDown()
+			if cfg.DrainDelay > 0 {
+				log.Info("Draining before shutdown", "delay", cfg.DrainDelay)
+				select {
+				case <-time.After(cfg.DrainDelay):
+				case <-ctx.Done():
+				}
+			}
+
+			return shutdown(ctx, server, in.Connections, cfg.ShutdownTimeout, log)
 		},
 	})
 }
+
+// shutdown 在 timeout 内优雅关闭服务器
+// 普通请求由 http.Server.Shutdown 等待完成，长连接由注册表通知关闭；
+// 超时后强制关闭剩余连接
+func shutdown(ctx context.Context, server *http.Server, conns *Connections, timeout time.Duration, log *slog.Logger) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	log.Info("Shutting down HTTP server...", "timeout", timeout, "long_lived", conns.Len())
+
+	connsErr := make(chan error, 1)
+	go func() {
+		connsErr <- conns.Shutdown(ctx)
+	}()
+
+	err := server.Shutdown(ctx)
+	if cerr := <-connsErr; cerr != nil && !errors.Is(cerr, context.DeadlineExceeded) {
+		log.Warn("Long-lived connections closed with error", "error", cerr)
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		log.Warn("Graceful shutdown timed out, closing remaining connections", "remaining", conns.Len())
+		return server.Close()
+	}
+	return err
+}
@@ -0,0 +1,194 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"artisan-coder/internal/config"
+)
+
+// reloadDebounce 证书文件变化后的合并等待时间
+// cert 和 key 通常先后写入，避免读到不匹配的一对
+const reloadDebounce = 500 * time.Millisecond
+
+// certReloader 监听证书文件变化并热加载
+// 已建立的连接继续使用旧证书，新握手使用新证书
+type certReloader struct {
+	cfg config.TLSConfig
+	log *slog.Logger
+
+	mu       sync.RWMutex
+	cert     *tls.Certificate
+	clientCA *x509.CertPool
+}
+
+func newCertReloader(cfg config.TLSConfig, log *slog.Logger) (*certReloader, error) {
+	r := &certReloader{cfg: cfg, log: log}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// reload 重新读取证书、私钥和客户端 CA，失败时保留旧证书
+func (r *certReloader) reload() error {
+	cert, err := tls.LoadX509KeyPair(r.cfg.CertFile, r.cfg.KeyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS key pair: %w", err)
+	}
+
+	var pool *x509.CertPool
+	if r.cfg.ClientCAFile != "" {
+		pem, err := os.ReadFile(r.cfg.ClientCAFile)
+		if err != nil {
+			return fmt.Errorf("failed to read client CA: %w", err)
+		}
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return errors.New("no certificates found in client CA file")
+		}
+	}
+
+	r.mu.Lock()
+	r.cert = &cert
+	r.clientCA = pool
+	r.mu.Unlock()
+	return nil
+}
+
+func (r *certReloader) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert, nil
+}
+
+func (r *certReloader) getClientCA() *x509.CertPool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.clientCA
+}
+
+// watch 监听证书所在目录直到 ctx 取消
+// 监听目录而非文件，兼容 Kubernetes Secret 的符号链接原子替换
+func (r *certReloader) watch(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+
+	dirs := map[string]struct{}{}
+	for _, file := range []string{r.cfg.CertFile, r.cfg.KeyFile, r.cfg.ClientCAFile} {
+		if file != "" {
+			dirs[filepath.Dir(file)] = struct{}{}
+		}
+	}
+	for dir := range dirs {
+		if err := watcher.Add(dir); err != nil {
+			watcher.Close()
+			return fmt.Errorf("failed to watch %s: %w", dir, err)
+		}
+	}
+
+	go func() {
+		defer watcher.Close()
+
+		var timer <-chan time.Time
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if event.Has(fsnotify.Write) || event.Has(fsnotify.Create) || event.Has(fsnotify.Rename) || event.Has(fsnotify.Remove) {
+					timer = time.After(reloadDebounce)
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				r.log.Warn("TLS certificate watcher error", "error", err)
+			case <-timer:
+				timer = nil
+				if err := r.reload(); err != nil {
+					r.log.Error("TLS certificate reload failed, keeping previous certificate", "error", err)
+					continue
+				}
+				r.log.Info("TLS certificate reloaded", "cert", r.cfg.CertFile)
+			}
+		}
+	}()
+
+	return nil
+}
+
+// newTLSConfig 根据配置创建 tls.Config
+func newTLSConfig(cfg config.TLSConfig, reloader *certReloader) (*tls.Config, error) {
+	minVersion, err := parseTLSVersion(cfg.MinVersion)
+	if err != nil {
+		return nil, err
+	}
+	clientAuth, err := parseClientAuth(cfg.ClientAuth)
+	if err != nil {
+		return nil, err
+	}
+	if clientAuth >= tls.VerifyClientCertIfGiven && cfg.ClientCAFile == "" {
+		return nil, errors.New("tls.clientCAFile is required to verify client certificates")
+	}
+
+	base := &tls.Config{
+		MinVersion:     minVersion,
+		ClientAuth:     clientAuth,
+		GetCertificate: reloader.getCertificate,
+		NextProtos:     []string{"h2", "http/1.1"},
+	}
+
+	// 每次握手取最新的客户端 CA
+	base.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		c := base.Clone()
+		c.GetConfigForClient = nil
+		c.ClientCAs = reloader.getClientCA()
+		return c, nil
+	}
+
+	return base, nil
+}
+
+func parseTLSVersion(v string) (uint16, error) {
+	switch v {
+	case "", "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("unsupported TLS min version %q", v)
+	}
+}
+
+func parseClientAuth(v string) (tls.ClientAuthType, error) {
+	switch v {
+	case "", "none":
+		return tls.NoClientCert, nil
+	case "request":
+		return tls.RequestClientCert, nil
+	case "require":
+		return tls.RequireAnyClientCert, nil
+	case "verify_if_given":
+		return tls.VerifyClientCertIfGiven, nil
+	case "require_and_verify":
+		return tls.RequireAndVerifyClientCert, nil
+	default:
+		return 0, fmt.Errorf("unsupported TLS client auth %q", v)
+	}
+}
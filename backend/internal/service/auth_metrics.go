@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"artisan-coder/internal/metrics"
+	"artisan-coder/internal/models"
+)
+
+// instrumentedAuthService 记录认证业务指标的装饰器
+type instrumentedAuthService struct {
+	AuthService
+	metrics *metrics.Metrics
+}
+
+// NewInstrumentedAuthService 为 AuthService 增加注册、登录、刷新计数
+func NewInstrumentedAuthService(next AuthService, m *metrics.Metrics) AuthService {
+	return &instrumentedAuthService{
+		AuthService: next,
+		metrics:     m,
+	}
+}
+
+func (s *instrumentedAuthService) Register(ctx context.Context, username, email, userPassword string) (*models.User, string, string, error) {
+	user, accessToken, refreshToken, err := s.AuthService.Register(ctx, username, email, userPassword)
+	s.metrics.Registrations.WithLabelValues(metrics.Result(err, registerFailures...)).Inc()
+	return user, accessToken, refreshToken, err
+}
+
+func (s *instrumentedAuthService) Login(ctx context.Context, email, userPassword string) (*models.User, string, string, error) {
+	user, accessToken, refreshToken, err := s.AuthService.Login(ctx, email, userPassword)
+	s.metrics.Logins.WithLabelValues(metrics.Result(err, loginFailures...)).Inc()
+	return user, accessToken, refreshToken, err
+}
+
+func (s *instrumentedAuthService) RefreshToken(ctx context.Context, refreshToken string) (*models.User, string, string, error) {
+	user, accessToken, newRefreshToken, err := s.AuthService.RefreshToken(ctx, refreshToken)
+	s.metrics.Refreshes.WithLabelValues(metrics.Result(err, refreshFailures...)).Inc()
+	return user, accessToken, newRefreshToken, err
+}
+
+func (s *instrumentedAuthService) GetUserByID(ctx context.Context, userID uuid.UUID) (*models.User, error) {
+	return s.AuthService.GetUserByID(ctx, userID)
+}
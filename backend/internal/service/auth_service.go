@@ -5,14 +5,30 @@ import (
 	"errors"
 
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/fx"
 
+	"artisan-coder/internal/metrics"
 	"artisan-coder/internal/models"
 	"artisan-coder/internal/repository"
+	"artisan-coder/pkg/clock"
 	"artisan-coder/pkg/jwt"
 	"artisan-coder/pkg/password"
 )
 
+var (
+	ErrInvalidCredentials  = errors.New("invalid credentials")
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	ErrUserDisabled        = errors.New("user disabled")
+)
+
+// 预期内的业务失败，指标记为 failure，span 不标记为错误
+var (
+	registerFailures = []error{repository.ErrUserAlreadyExists}
+	loginFailures    = []error{ErrInvalidCredentials, ErrUserDisabled}
+	refreshFailures  = []error{ErrInvalidRefreshToken, ErrUserDisabled}
+)
+
 type AuthService interface {
 	Register(ctx context.Context, username, email, userPassword string) (*models.User, string, string, error)
 	Login(ctx context.Context, email, userPassword string) (*models.User, string, string, error)
@@ -22,39 +38,57 @@ type AuthService interface {
 
 type authService struct {
 	userRepo   repository.UserRepository
+	txManager  repository.TxManager
 	jwtManager *jwt.Manager
+	clock      clock.Clock
+	tracer     trace.Tracer
 }
 
-func NewAuthService(userRepo repository.UserRepository, jwtManager *jwt.Manager) AuthService {
+func NewAuthService(userRepo repository.UserRepository, txManager repository.TxManager, jwtManager *jwt.Manager, clk clock.Clock, tp trace.TracerProvider) AuthService {
 	return &authService{
 		userRepo:   userRepo,
+		txManager:  txManager,
 		jwtManager: jwtManager,
+		clock:      clk,
+		tracer:     tp.Tracer(tracerName),
 	}
 }
 
 func (s *authService) Register(ctx context.Context, username, email, userPassword string) (*models.User, string, string, error) {
-	// 检查用户是否已存在
-	_, err := s.userRepo.FindByEmail(ctx, email)
-	if err == nil {
-		return nil, "", "", repository.ErrUserAlreadyExists
-	} else if !errors.Is(err, repository.ErrUserNotFound) {
-		return nil, "", "", err
-	}
-
-	// 加密密码
+	// 加密密码（耗时操作，放在事务外避免长时间占用连接）
+	_, span := s.tracer.Start(ctx, "password.Hash")
 	hashedPassword, err := password.Hash(userPassword)
+	span.End()
 	if err != nil {
 		return nil, "", "", err
 	}
 
-	// 创建用户
 	user := &models.User{
 		Username:     username,
 		Email:        email,
 		PasswordHash: hashedPassword,
 	}
 
-	if err := s.userRepo.Create(ctx, user); err != nil {
+	// 检查和创建在同一事务中完成
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		// 检查用户是否已存在；并发注册由唯一约束兜底
+		_, err := s.userRepo.FindByEmail(ctx, email)
+		if err == nil {
+			return repository.ErrEmailTaken
+		} else if !errors.Is(err, repository.ErrUserNotFound) {
+			return err
+		}
+		_, err = s.userRepo.FindByUsername(ctx, username)
+		if err == nil {
+			return repository.ErrUsernameTaken
+		} else if !errors.Is(err, repository.ErrUserNotFound) {
+			return err
+		}
+
+		// 创建用户
+		return s.userRepo.Create(ctx, user)
+	})
+	if err != nil {
 		return nil, "", "", err
 	}
 
@@ -72,16 +106,30 @@ func (s *authService) Login(ctx context.Context, email, userPassword string) (*m
 	user, err := s.userRepo.FindByEmail(ctx, email)
 	if err != nil {
 		if errors.Is(err, repository.ErrUserNotFound) {
-			return nil, "", "", errors.New("invalid credentials")
+			return nil, "", "", ErrInvalidCredentials
 		}
 		return nil, "", "", err
 	}
 
 	// 验证密码
-	if !password.Verify(user.PasswordHash, userPassword) {
-		return nil, "", "", errors.New("invalid credentials")
+	_, span := s.tracer.Start(ctx, "password.Verify")
+	ok := password.Verify(user.PasswordHash, userPassword)
+	span.End()
+	if !ok {
+		return nil, "", "", ErrInvalidCredentials
 	}
 
+	// 密码正确后再判断禁用，避免泄露账号状态
+	if user.Disabled() {
+		return nil, "", "", ErrUserDisabled
+	}
+
+	now := s.clock.Now()
+	if err := s.userRepo.RecordLogin(ctx, user.ID, now); err != nil {
+		return nil, "", "", err
+	}
+	user.LastLoginAt = &now
+
 	// 生成 Token
 	accessToken, refreshToken, err := s.jwtManager.GenerateTokenPair(user.ID, user.Email)
 	if err != nil {
@@ -95,7 +143,7 @@ func (s *authService) RefreshToken(ctx context.Context, refreshToken string) (*m
 	// 验证并刷新 Token
 	accessToken, newRefreshToken, err := s.jwtManager.RefreshToken(refreshToken)
 	if err != nil {
-		return nil, "", "", errors.New("invalid or expired refresh token")
+		return nil, "", "", ErrInvalidRefreshToken
 	}
 
 	// 从 Access Token 中获取用户信息
@@ -104,11 +152,17 @@ func (s *authService) RefreshToken(ctx context.Context, refreshToken string) (*m
 		return nil, "", "", err
 	}
 
-	// 获取用户信息
+	// 获取用户信息，已删除或禁用的用户不能续期
 	user, err := s.userRepo.FindByID(ctx, claims.UserID)
 	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil, "", "", ErrInvalidRefreshToken
+		}
 		return nil, "", "", err
 	}
+	if user.Disabled() {
+		return nil, "", "", ErrUserDisabled
+	}
 
 	return user, accessToken, newRefreshToken, nil
 }
@@ -119,7 +173,14 @@ func (s *authService) GetUserByID(ctx context.Context, userID uuid.UUID) (*model
 
 // Module 返回 Service 模块的 FX 选项
 func Module() fx.Option {
-	return fx.Provide(
-		NewAuthService,
+	return fx.Options(
+		fx.Provide(NewAuthService, NewUserStatusChecker),
+		fx.Decorate(decorateAuthService),
 	)
 }
+
+// decorateAuthService 依次叠加追踪和指标装饰器
+// 同一类型在模块内只能注册一个 fx.Decorate
+func decorateAuthService(next AuthService, tp trace.TracerProvider, m *metrics.Metrics) AuthService {
+	return NewInstrumentedAuthService(NewTracedAuthService(next, tp), m)
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"artisan-coder/internal/models"
+)
+
+const tracerName = "artisan-coder/internal/service"
+
+// tracedAuthService 为认证业务方法创建 span 的装饰器
+type tracedAuthService struct {
+	next   AuthService
+	tracer trace.Tracer
+}
+
+// NewTracedAuthService 为 AuthService 增加链路追踪
+func NewTracedAuthService(next AuthService, tp trace.TracerProvider) AuthService {
+	return &tracedAuthService{
+		next:   next,
+		tracer: tp.Tracer(tracerName),
+	}
+}
+
+func (s *tracedAuthService) Register(ctx context.Context, username, email, userPassword string) (*models.User, string, string, error) {
+	ctx, span := s.tracer.Start(ctx, "AuthService.Register")
+	defer span.End()
+
+	user, accessToken, refreshToken, err := s.next.Register(ctx, username, email, userPassword)
+	recordUser(span, user)
+	return user, accessToken, refreshToken, record(span, err, registerFailures...)
+}
+
+func (s *tracedAuthService) Login(ctx context.Context, email, userPassword string) (*models.User, string, string, error) {
+	ctx, span := s.tracer.Start(ctx, "AuthService.Login")
+	defer span.End()
+
+	user, accessToken, refreshToken, err := s.next.Login(ctx, email, userPassword)
+	recordUser(span, user)
+	return user, accessToken, refreshToken, record(span, err, loginFailures...)
+}
+
+func (s *tracedAuthService) RefreshToken(ctx context.Context, refreshToken string) (*models.User, string, string, error) {
+	ctx, span := s.tracer.Start(ctx, "AuthService.RefreshToken")
+	defer span.End()
+
+	user, accessToken, newRefreshToken, err := s.next.RefreshToken(ctx, refreshToken)
+	recordUser(span, user)
+	return user, accessToken, newRefreshToken, record(span, err, refreshFailures...)
+}
+
+func (s *tracedAuthService) GetUserByID(ctx context.Context, userID uuid.UUID) (*models.User, error) {
+	ctx, span := s.tracer.Start(ctx, "AuthService.GetUserByID",
+		trace.WithAttributes(attribute.String("user.id", userID.String())))
+	defer span.End()
+
+	user, err := s.next.GetUserByID(ctx, userID)
+	return user, record(span, err)
+}
+
+func recordUser(span trace.Span, user *models.User) {
+	if user != nil {
+		span.SetAttributes(attribute.String("user.id", user.ID.String()))
+	}
+}
+
+// record 记录错误；预期内的业务失败只作为事件，不标记 span 为错误
+func record(span trace.Span, err error, expected ...error) error {
+	if err == nil {
+		return nil
+	}
+	for _, target := range expected {
+		if errors.Is(err, target) {
+			span.AddEvent(err.Error())
+			return err
+		}
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+	return err
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"artisan-coder/internal/repository"
+)
+
+// ErrUserDeleted 令牌所属用户已删除
+var ErrUserDeleted = errors.New("user deleted")
+
+// UserStatusChecker 校验令牌所属用户是否仍可用
+// 按 ID 查询由 UserRepository 的缓存装饰器承担，见 userCache 配置
+type UserStatusChecker struct {
+	userRepo repository.UserRepository
+}
+
+// NewUserStatusChecker 创建用户状态校验器
+func NewUserStatusChecker(userRepo repository.UserRepository) *UserStatusChecker {
+	return &UserStatusChecker{userRepo: userRepo}
+}
+
+// Check 用户可用时返回 nil，已禁用返回 ErrUserDisabled，已删除返回 ErrUserDeleted
+func (c *UserStatusChecker) Check(ctx context.Context, userID uuid.UUID) error {
+	user, err := c.userRepo.FindByID(ctx, userID)
+	switch {
+	case errors.Is(err, repository.ErrUserNotFound):
+		return ErrUserDeleted
+	case err != nil:
+		return err
+	case user.Disabled():
+		return ErrUserDisabled
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package testkit
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"artisan-coder/internal/handler"
+)
+
+// Response 已读取响应体的 HTTP 响应
+type Response struct {
+	*http.Response
+	Body []byte
+}
+
+// envelope 统一响应信封 {code,message,data}
+type envelope struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// Data 将信封中的 data 解析到 v
+func (r *Response) Data(t testing.TB, v interface{}) {
+	t.Helper()
+	var env envelope
+	if err := json.Unmarshal(r.Body, &env); err != nil {
+		t.Fatalf("testkit: decode response %q: %v", r.Body, err)
+	}
+	if err := json.Unmarshal(env.Data, v); err != nil {
+		t.Fatalf("testkit: decode data %q: %v", env.Data, err)
+	}
+}
+
+// RequireStatus 状态码不符时终止测试并输出响应体
+func (r *Response) RequireStatus(t testing.TB, status int) *Response {
+	t.Helper()
+	if r.StatusCode != status {
+		t.Fatalf("testkit: %s %s: status %d, want %d; body: %s",
+			r.Request.Method, r.Request.URL.Path, r.StatusCode, status, r.Body)
+	}
+	return r
+}
+
+// RequestOption 请求选项
+type RequestOption func(*http.Request)
+
+// WithToken 设置 Bearer 令牌
+func WithToken(token string) RequestOption {
+	return WithHeader("Authorization", "Bearer "+token)
+}
+
+// WithHeader 设置请求头
+func WithHeader(key, value string) RequestOption {
+	return func(req *http.Request) {
+		req.Header.Set(key, value)
+	}
+}
+
+// Do 发送请求，body 非 nil 时编码为 JSON
+func (k *Kit) Do(t testing.TB, method, path string, body interface{}, opts ...RequestOption) *Response {
+	t.Helper()
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("testkit: encode body: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, k.URL+path, reader)
+	if err != nil {
+		t.Fatalf("testkit: new request: %v", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for _, opt := range opts {
+		opt(req)
+	}
+
+	resp, err := k.Client.Do(req)
+	if err != nil {
+		t.Fatalf("testkit: %s %s: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("testkit: read body: %v", err)
+	}
+	return &Response{Response: resp, Body: data}
+}
+
+// Register 注册用户，要求成功
+func (k *Kit) Register(t testing.TB, username, email, password string) *handler.AuthResponse {
+	t.Helper()
+	var auth handler.AuthResponse
+	k.Do(t, http.MethodPost, "/api/auth/register", handler.RegisterRequest{
+		Username:        username,
+		Email:           email,
+		Password:        password,
+		ConfirmPassword: password,
+	}).RequireStatus(t, http.StatusCreated).Data(t, &auth)
+	return &auth
+}
+
+// Login 登录，要求成功
+func (k *Kit) Login(t testing.TB, email, password string) *handler.AuthResponse {
+	t.Helper()
+	var auth handler.AuthResponse
+	k.Do(t, http.MethodPost, "/api/auth/login", handler.LoginRequest{
+		Email:    email,
+		Password: password,
+	}).RequireStatus(t, http.StatusOK).Data(t, &auth)
+	return &auth
+}
@@ -0,0 +1,157 @@
+// Package testkit 启动完整的 fx 依赖图并挂到 httptest.Server 上，用于端到端 API 测试
+//
+//	kit := testkit.New(t)
+//	tokens := kit.Register(t, "alice", "alice@example.com", "password123")
+//	kit.Clock.Advance(2 * time.Hour)
+//	resp := kit.Do(t, http.MethodGet, "/api/auth/me", nil, testkit.WithToken(tokens.Token))
+package testkit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/fx"
+
+	"artisan-coder/internal/app"
+	"artisan-coder/internal/config"
+	"artisan-coder/internal/database"
+	"artisan-coder/internal/repository"
+	"artisan-coder/pkg/clock"
+	"artisan-coder/pkg/idgen"
+)
+
+// startTimeout 启动和停止依赖图的超时
+const startTimeout = 15 * time.Second
+
+// Kit 运行中的测试应用，测试结束时自动停止
+type Kit struct {
+	URL    string
+	Client *http.Client
+	Clock  *clock.Fake    // 注入依赖图的时钟，拨动它即可让令牌过期
+	Config *config.Config // 覆盖后的最终配置
+
+	// UserRepository 依赖图中的用户仓储（包含追踪等装饰），便于直接准备数据
+	UserRepository repository.UserRepository
+}
+
+// Option 测试应用选项
+type Option func(*options)
+
+type options struct {
+	configure []func(*config.Config)
+	fxOptions []fx.Option
+	memory    bool
+}
+
+// WithConfig 修改配置，在默认的测试配置之后执行
+func WithConfig(fn func(cfg *config.Config)) Option {
+	return func(o *options) {
+		o.configure = append(o.configure, fn)
+	}
+}
+
+// WithFx 追加 fx 选项，通常是 fx.Replace 或 fx.Decorate
+// 这些选项位于应用模块的父作用域，替换的值会再经过应用自身的装饰器（如追踪）
+func WithFx(opts ...fx.Option) Option {
+	return func(o *options) {
+		o.fxOptions = append(o.fxOptions, opts...)
+	}
+}
+
+// WithMemoryRepositories 使用内存仓储替代数据库实现
+// 内存事务不回滚，依赖回滚语义的测试保持默认的 SQLite
+func WithMemoryRepositories() Option {
+	return func(o *options) {
+		o.memory = true
+	}
+}
+
+// New 启动测试应用
+// 默认使用内存 SQLite 并自动迁移，关闭限流，日志只输出错误
+func New(t testing.TB, opts ...Option) *Kit {
+	t.Helper()
+
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	kit := &Kit{
+		Clock: clock.NewFake(time.Now().UTC().Truncate(time.Second)),
+	}
+
+	overrides := []fx.Option{
+		fx.Decorate(func(cfg *config.Config) *config.Config {
+			return testConfig(cfg, o.configure)
+		}),
+		fx.Decorate(func(clock.Clock) clock.Clock { return kit.Clock }),
+	}
+	if o.memory {
+		overrides = append(overrides, fx.Decorate(
+			func(_ repository.UserRepository, clk clock.Clock, ids idgen.IDGenerator) repository.UserRepository {
+				return repository.NewMemoryUserRepository(clk, ids)
+			},
+			func(repository.TxManager) repository.TxManager { return repository.NewMemoryTxManager() },
+		))
+	}
+
+	var engine *gin.Engine
+	fxApp := fx.New(
+		// 应用日志在模块内配置，根作用域的 fx 事件日志不输出
+		fx.NopLogger,
+		fx.Options(overrides...),
+		fx.Options(o.fxOptions...),
+		// 覆盖项在父作用域，应用模块内的装饰器在其基础上继续装饰
+		fx.Module("app", app.CoreModule(),
+			fx.Populate(&engine, &kit.Config, &kit.UserRepository),
+		),
+	)
+	if err := fxApp.Err(); err != nil {
+		t.Fatalf("testkit: build app: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), startTimeout)
+	defer cancel()
+	if err := fxApp.Start(ctx); err != nil {
+		t.Fatalf("testkit: start app: %v", err)
+	}
+
+	server := httptest.NewServer(engine)
+	kit.URL = server.URL
+	kit.Client = server.Client()
+
+	t.Cleanup(func() {
+		server.Close()
+		ctx, cancel := context.WithTimeout(context.Background(), startTimeout)
+		defer cancel()
+		if err := fxApp.Stop(ctx); err != nil {
+			t.Errorf("testkit: stop app: %v", err)
+		}
+	})
+
+	return kit
+}
+
+// testConfig 在加载的配置上套用测试默认值
+func testConfig(base *config.Config, configure []func(*config.Config)) *config.Config {
+	cfg := *base
+	cfg.Server.Mode = gin.TestMode
+	cfg.Database.Driver = database.DriverSQLite
+	cfg.Database.Path = ":memory:"
+	cfg.Database.AutoMigrate = true
+	cfg.Database.Replicas = nil
+	cfg.Log.Level = "error"
+	cfg.RateLimit.Enabled = false
+	cfg.Metrics.Port = ""
+	cfg.Tracing.Exporter = "none"
+	cfg.Admin.Enabled = false
+
+	for _, fn := range configure {
+		fn(&cfg)
+	}
+	return &cfg
+}
@@ -0,0 +1,99 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"go.uber.org/fx"
+
+	"artisan-coder/internal/config"
+)
+
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+)
+
+// Module 返回链路追踪模块的 FX 选项
+func Module() fx.Option {
+	return fx.Provide(
+		NewExporter,
+		NewTracerProvider,
+	)
+}
+
+// NewExporter 按配置创建 span 导出器，none 时返回 nil
+func NewExporter(cfg *config.Config) (sdktrace.SpanExporter, error) {
+	switch cfg.Tracing.Exporter {
+	case "", ExporterNone:
+		return nil, nil
+	case ExporterStdout:
+		return stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case ExporterOTLP:
+		opts := []otlptracehttp.Option{}
+		if cfg.Tracing.Endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpoint(cfg.Tracing.Endpoint))
+		}
+		if cfg.Tracing.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		return otlptracehttp.New(context.Background(), opts...)
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %q", cfg.Tracing.Exporter)
+	}
+}
+
+// NewTracerProvider 创建 TracerProvider 并设置为全局默认
+// 同时注册 W3C Trace Context 和 Baggage 传播器
+func NewTracerProvider(lc fx.Lifecycle, cfg *config.Config, exporter sdktrace.SpanExporter) (trace.TracerProvider, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	if exporter == nil {
+		tp := noop.NewTracerProvider()
+		otel.SetTracerProvider(tp)
+		return tp, nil
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(cfg.Tracing.ServiceName),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tracing resource: %w", err)
+	}
+
+	tp := NewSDKProvider(exporter, res, cfg.Tracing.SampleRatio)
+	otel.SetTracerProvider(tp)
+
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			return tp.Shutdown(ctx)
+		},
+	})
+
+	return tp, nil
+}
+
+// NewSDKProvider 创建批量导出的 SDK TracerProvider
+// 采样遵循上游决定，根 span 按比例采样
+func NewSDKProvider(exporter sdktrace.SpanExporter, res *resource.Resource, sampleRatio float64) *sdktrace.TracerProvider {
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+	)
+}
@@ -0,0 +1,25 @@
+// Package migrations 内嵌数据库迁移 SQL，随二进制发布
+// 每种数据库驱动一个目录，版本号保持一致
+package migrations
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+)
+
+// Drivers 提供迁移文件的数据库驱动
+var Drivers = []string{"postgres", "sqlite"}
+
+//go:embed postgres/*.sql sqlite/*.sql
+var files embed.FS
+
+// FS 返回指定驱动的迁移文件
+func FS(driver string) (fs.FS, error) {
+	for _, d := range Drivers {
+		if d == driver {
+			return fs.Sub(files, driver)
+		}
+	}
+	return nil, fmt.Errorf("no migrations for database driver %q", driver)
+}
@@ -0,0 +1,5 @@
+-- updated_at 由 GORM 按注入的时钟维护，与 SQLite 一致
+-- 触发器会覆盖 UpdateColumn（如 last_login_at）和软删除的 updated_at
+-- 旧版 AutoMigrate 创建的库没有触发器
+DROP TRIGGER IF EXISTS update_users_updated_at ON users;
+DROP FUNCTION IF EXISTS update_updated_at_column();
@@ -0,0 +1,8 @@
+-- 仅为保持迁移版本一致，SQLite 下不支持 postgres 限流存储
+CREATE TABLE rate_limits (
+    key VARCHAR(255) PRIMARY KEY NOT NULL,
+    value BIGINT NOT NULL DEFAULT 0,
+    expires_at DATETIME NOT NULL
+);
+
+CREATE INDEX idx_rate_limits_expires_at ON rate_limits(expires_at);
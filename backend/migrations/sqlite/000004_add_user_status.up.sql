@@ -0,0 +1,7 @@
+-- SQLite 版本：DROP COLUMN 不能删除外键列，created_by 不加外键
+ALTER TABLE users ADD COLUMN deleted_at DATETIME;
+ALTER TABLE users ADD COLUMN disabled_at DATETIME;
+ALTER TABLE users ADD COLUMN last_login_at DATETIME;
+ALTER TABLE users ADD COLUMN created_by UUID;
+
+CREATE INDEX idx_users_deleted_at ON users(deleted_at);
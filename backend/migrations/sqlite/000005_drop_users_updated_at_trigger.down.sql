@@ -0,0 +1,2 @@
+-- SQLite 没有 updated_at 触发器
+SELECT 1;
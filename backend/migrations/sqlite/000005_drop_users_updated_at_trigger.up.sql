@@ -0,0 +1,2 @@
+-- SQLite 没有 updated_at 触发器，仅保持与 Postgres 的版本号一致
+SELECT 1;
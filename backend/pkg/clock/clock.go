@@ -0,0 +1,110 @@
+package clock
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/fx"
+)
+
+// Clock 时间来源
+// 依赖当前时间或定时执行的组件通过它取时间，测试中可替换为 Fake
+type Clock interface {
+	Now() time.Time
+	// After 在 d 之后发送当时的时间，同 time.After
+	After(d time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+// New 返回系统时钟
+func New() Clock {
+	return realClock{}
+}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// Fake 手动控制的时钟，并发安全
+// 时间只在 Set、Advance 时变化，到期的 After 随之触发
+type Fake struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []waiter
+}
+
+type waiter struct {
+	at time.Time
+	ch chan time.Time
+}
+
+// NewFake 创建停在 t 的时钟
+func NewFake(t time.Time) *Fake {
+	return &Fake{now: t}
+}
+
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+func (f *Fake) After(d time.Duration) <-chan time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- f.now
+		return ch
+	}
+	f.waiters = append(f.waiters, waiter{at: f.now.Add(d), ch: ch})
+	return ch
+}
+
+// Set 将时钟设置到 t
+func (f *Fake) Set(t time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = t
+	f.fire()
+}
+
+// Advance 将时钟向前拨 d
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+	f.fire()
+}
+
+// Waiters 返回尚未触发的 After 数量
+// 测试中用它确认定时任务已进入等待，再拨动时钟
+func (f *Fake) Waiters() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.waiters)
+}
+
+// fire 触发到期的 After，调用方需持有锁
+func (f *Fake) fire() {
+	pending := f.waiters[:0]
+	for _, w := range f.waiters {
+		if w.at.After(f.now) {
+			pending = append(pending, w)
+			continue
+		}
+		w.ch <- f.now
+	}
+	f.waiters = pending
+}
+
+// Module 返回时钟模块的 FX 选项
+func Module() fx.Option {
+	return fx.Provide(New)
+}
@@ -0,0 +1,136 @@
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.uber.org/fx"
+)
+
+// Locale 语言标识（BCP 47）
+type Locale string
+
+const (
+	LocaleEN   Locale = "en"
+	LocaleZhCN Locale = "zh-CN"
+
+	// DefaultLocale 默认语言，未知 key 也回退到该语言
+	DefaultLocale = LocaleEN
+)
+
+// GinKey 语言在 gin.Context 中的存储键
+const GinKey = "locale"
+
+type contextKey struct{}
+
+// catalogues 消息目录
+var catalogues = map[Locale]map[string]string{
+	LocaleEN:   messagesEN,
+	LocaleZhCN: messagesZhCN,
+}
+
+// Supported 返回支持的语言列表
+func Supported() []Locale {
+	return []Locale{LocaleEN, LocaleZhCN}
+}
+
+// Parse 将语言标签规范化为支持的 Locale
+// 支持 zh、zh-CN、zh_cn、zh-Hans 等写法
+func Parse(tag string) (Locale, bool) {
+	tag = strings.ToLower(strings.TrimSpace(strings.ReplaceAll(tag, "_", "-")))
+	if tag == "" {
+		return "", false
+	}
+
+	switch {
+	case tag == "en" || strings.HasPrefix(tag, "en-"):
+		return LocaleEN, true
+	case tag == "zh" || tag == "zh-cn" || tag == "zh-sg" || strings.HasPrefix(tag, "zh-hans"):
+		return LocaleZhCN, true
+	}
+	return "", false
+}
+
+// Negotiate 根据 Accept-Language 头选择最匹配的语言
+// 例如：zh-CN,zh;q=0.9,en;q=0.8 -> zh-CN
+func Negotiate(acceptLanguage string) Locale {
+	type candidate struct {
+		tag string
+		q   float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		tag, q := part, 1.0
+		if i := strings.Index(part, ";"); i >= 0 {
+			tag = strings.TrimSpace(part[:i])
+			for _, param := range strings.Split(part[i+1:], ";") {
+				param = strings.TrimSpace(param)
+				if v, ok := strings.CutPrefix(param, "q="); ok {
+					if f, err := strconv.ParseFloat(v, 64); err == nil {
+						q = f
+					}
+				}
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+		candidates = append(candidates, candidate{tag: tag, q: q})
+	}
+
+	// 按权重降序，权重相同时保持原始顺序
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].q > candidates[j].q
+	})
+
+	for _, c := range candidates {
+		if locale, ok := Parse(c.tag); ok {
+			return locale
+		}
+	}
+	return DefaultLocale
+}
+
+// T 翻译消息 key，支持 fmt 风格参数
+// 查找顺序：指定语言 -> 英文 -> key 本身
+func T(locale Locale, key string, args ...interface{}) string {
+	msg, ok := catalogues[locale][key]
+	if !ok {
+		msg, ok = catalogues[DefaultLocale][key]
+	}
+	if !ok {
+		msg = key
+	}
+
+	if len(args) > 0 {
+		return fmt.Sprintf(msg, args...)
+	}
+	return msg
+}
+
+// WithLocale 将语言存入 context
+func WithLocale(ctx context.Context, locale Locale) context.Context {
+	return context.WithValue(ctx, contextKey{}, locale)
+}
+
+// FromContext 从 context 获取语言，不存在时返回默认语言
+func FromContext(ctx context.Context) Locale {
+	if locale, ok := ctx.Value(contextKey{}).(Locale); ok {
+		return locale
+	}
+	return DefaultLocale
+}
+
+// Module 返回 i18n 模块的 FX 选项
+func Module() fx.Option {
+	return fx.Invoke(RegisterValidator)
+}
@@ -0,0 +1,82 @@
+package i18n
+
+// 消息 key 定义
+const (
+	// 通用
+	KeySuccess         = "success"
+	KeyBadRequest      = "common.bad_request"
+	KeyUnauthorized    = "common.unauthorized"
+	KeyForbidden       = "common.forbidden"
+	KeyNotFound        = "common.not_found"
+	KeyConflict        = "common.conflict"
+	KeyTooManyRequests = "common.too_many_requests"
+	KeyInternalError   = "common.internal_error"
+
+	// 认证
+	KeyPasswordsMismatch     = "auth.passwords_mismatch"
+	KeyUserEmailExists       = "auth.user_email_exists"
+	KeyUsernameTaken         = "auth.username_taken"
+	KeyInvalidCredentials    = "auth.invalid_credentials"
+	KeyMissingRefreshToken   = "auth.missing_refresh_token"
+	KeyInvalidRefreshToken   = "auth.invalid_refresh_token"
+	KeyNotAuthenticated      = "auth.not_authenticated"
+	KeyInvalidUserID         = "auth.invalid_user_id"
+	KeyMissingAuthToken      = "auth.missing_token"
+	KeyInvalidAuthFormat     = "auth.invalid_format"
+	KeyInvalidOrExpiredToken = "auth.invalid_or_expired_token"
+	KeyAccountDisabled       = "auth.account_disabled"
+	KeyInvalidRequestBody    = "validation.invalid_body"
+	KeyValidationFailed      = "validation.failed"
+)
+
+var messagesEN = map[string]string{
+	KeySuccess:         "success",
+	KeyBadRequest:      "Bad request",
+	KeyUnauthorized:    "Unauthorized",
+	KeyForbidden:       "Forbidden",
+	KeyNotFound:        "Not found",
+	KeyConflict:        "Conflict",
+	KeyTooManyRequests: "Too many requests, please try again later",
+	KeyInternalError:   "Internal server error",
+
+	KeyPasswordsMismatch:     "Passwords do not match",
+	KeyUserEmailExists:       "User with this email already exists",
+	KeyUsernameTaken:         "Username is already taken",
+	KeyInvalidCredentials:    "Invalid email or password",
+	KeyMissingRefreshToken:   "Missing refresh token",
+	KeyInvalidRefreshToken:   "Invalid or expired refresh token",
+	KeyNotAuthenticated:      "User not authenticated",
+	KeyInvalidUserID:         "Invalid user ID",
+	KeyMissingAuthToken:      "Missing authorization token",
+	KeyInvalidAuthFormat:     "Invalid authorization format",
+	KeyInvalidOrExpiredToken: "Invalid or expired token",
+	KeyAccountDisabled:       "This account has been disabled",
+	KeyInvalidRequestBody:    "Invalid request body",
+	KeyValidationFailed:      "Validation failed",
+}
+
+var messagesZhCN = map[string]string{
+	KeySuccess:         "成功",
+	KeyBadRequest:      "请求参数错误",
+	KeyUnauthorized:    "未授权",
+	KeyForbidden:       "禁止访问",
+	KeyNotFound:        "资源不存在",
+	KeyConflict:        "资源冲突",
+	KeyTooManyRequests: "请求过于频繁，请稍后再试",
+	KeyInternalError:   "服务器内部错误",
+
+	KeyPasswordsMismatch:     "两次输入的密码不一致",
+	KeyUserEmailExists:       "该邮箱已被注册",
+	KeyUsernameTaken:         "该用户名已被占用",
+	KeyInvalidCredentials:    "邮箱或密码错误",
+	KeyMissingRefreshToken:   "缺少刷新令牌",
+	KeyInvalidRefreshToken:   "刷新令牌无效或已过期",
+	KeyNotAuthenticated:      "用户未认证",
+	KeyInvalidUserID:         "无效的用户 ID",
+	KeyMissingAuthToken:      "缺少认证令牌",
+	KeyInvalidAuthFormat:     "认证格式无效",
+	KeyInvalidOrExpiredToken: "令牌无效或已过期",
+	KeyAccountDisabled:       "该账号已被禁用",
+	KeyInvalidRequestBody:    "请求体格式错误",
+	KeyValidationFailed:      "参数校验失败",
+}
@@ -0,0 +1,100 @@
+package i18n
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/zh"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	enTranslations "github.com/go-playground/validator/v10/translations/en"
+	zhTranslations "github.com/go-playground/validator/v10/translations/zh"
+)
+
+// FieldError 单个字段的校验错误
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+var (
+	registerOnce sync.Once
+	registerErr  error
+	translators  map[Locale]ut.Translator
+)
+
+// RegisterValidator 为 gin 默认校验器注册中英文翻译
+// 字段名使用 json tag，与前端提交的字段保持一致
+func RegisterValidator() error {
+	registerOnce.Do(func() {
+		v, ok := binding.Validator.Engine().(*validator.Validate)
+		if !ok {
+			registerErr = errors.New("unexpected validator engine")
+			return
+		}
+
+		v.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+			if name == "-" {
+				return ""
+			}
+			if name == "" {
+				return field.Name
+			}
+			return name
+		})
+
+		enLocale := en.New()
+		uni := ut.New(enLocale, enLocale, zh.New())
+
+		enTrans, _ := uni.GetTranslator("en")
+		zhTrans, _ := uni.GetTranslator("zh")
+
+		if err := enTranslations.RegisterDefaultTranslations(v, enTrans); err != nil {
+			registerErr = fmt.Errorf("failed to register en validator translations: %w", err)
+			return
+		}
+		if err := zhTranslations.RegisterDefaultTranslations(v, zhTrans); err != nil {
+			registerErr = fmt.Errorf("failed to register zh validator translations: %w", err)
+			return
+		}
+
+		translators = map[Locale]ut.Translator{
+			LocaleEN:   enTrans,
+			LocaleZhCN: zhTrans,
+		}
+	})
+	return registerErr
+}
+
+// TranslateValidationErrors 将校验错误翻译为字段错误列表
+// 非校验错误（如 JSON 格式错误）返回 nil
+func TranslateValidationErrors(locale Locale, err error) []FieldError {
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return nil
+	}
+
+	trans, ok := translators[locale]
+	if !ok {
+		trans = translators[DefaultLocale]
+	}
+
+	fields := make([]FieldError, 0, len(verrs))
+	for _, fe := range verrs {
+		msg := fe.Error()
+		if trans != nil {
+			msg = fe.Translate(trans)
+		}
+		fields = append(fields, FieldError{
+			Field:   fe.Field(),
+			Message: msg,
+		})
+	}
+	return fields
+}
@@ -0,0 +1,47 @@
+package idgen
+
+import (
+	"encoding/binary"
+	"sync/atomic"
+
+	"github.com/google/uuid"
+	"go.uber.org/fx"
+)
+
+// IDGenerator 生成实体主键
+// 测试中可替换为 Sequence 得到可预期的 ID
+type IDGenerator interface {
+	NewID() uuid.UUID
+}
+
+type randomGenerator struct{}
+
+// New 返回随机 UUID（v4）生成器
+func New() IDGenerator {
+	return randomGenerator{}
+}
+
+func (randomGenerator) NewID() uuid.UUID {
+	return uuid.New()
+}
+
+// Sequence 按顺序生成 00000000-0000-0000-0000-000000000001 起的 ID，并发安全
+type Sequence struct {
+	n atomic.Uint64
+}
+
+// NewSequence 创建从 1 开始的序列生成器
+func NewSequence() *Sequence {
+	return &Sequence{}
+}
+
+func (s *Sequence) NewID() uuid.UUID {
+	var id uuid.UUID
+	binary.BigEndian.PutUint64(id[8:], s.n.Add(1))
+	return id
+}
+
+// Module 返回 ID 生成器模块的 FX 选项
+func Module() fx.Option {
+	return fx.Provide(New)
+}
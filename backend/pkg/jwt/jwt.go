@@ -9,6 +9,7 @@ import (
 	"go.uber.org/fx"
 
 	"artisan-coder/internal/config"
+	"artisan-coder/pkg/clock"
 )
 
 type Claims struct {
@@ -22,14 +23,16 @@ type Manager struct {
 	accessDuration  time.Duration
 	refreshDuration time.Duration
 	issuer          string
+	clock           clock.Clock
 }
 
-func NewManager(secret string, accessDuration, refreshDuration time.Duration, issuer string) *Manager {
+func NewManager(secret string, accessDuration, refreshDuration time.Duration, issuer string, clk clock.Clock) *Manager {
 	return &Manager{
 		secret:          []byte(secret),
 		accessDuration:  accessDuration,
 		refreshDuration: refreshDuration,
 		issuer:          issuer,
+		clock:           clk,
 	}
 }
 
@@ -51,7 +54,7 @@ func (m *Manager) GenerateTokenPair(userID uuid.UUID, email string) (accessToken
 }
 
 func (m *Manager) generateToken(userID uuid.UUID, email string, duration time.Duration) (string, error) {
-	now := time.Now()
+	now := m.clock.Now()
 	claims := Claims{
 		UserID: userID,
 		Email:  email,
@@ -74,7 +77,7 @@ func (m *Manager) ValidateToken(tokenString string) (*Claims, error) {
 			return nil, errors.New("unexpected signing method")
 		}
 		return m.secret, nil
-	})
+	}, jwt.WithTimeFunc(m.clock.Now))
 
 	if err != nil {
 		return nil, err
@@ -106,11 +109,12 @@ func Module() fx.Option {
 }
 
 // NewManagerFromConfig 从配置创建 JWT Manager
-func NewManagerFromConfig(cfg *config.Config) *Manager {
+func NewManagerFromConfig(cfg *config.Config, clk clock.Clock) *Manager {
 	return NewManager(
 		cfg.JWT.Secret,
 		cfg.JWT.AccessDuration,
 		cfg.JWT.RefreshDuration,
 		cfg.JWT.Issuer,
+		clk,
 	)
 }
@@ -0,0 +1,56 @@
+package requestid
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// Header 请求 ID 的 HTTP 头
+	Header = "X-Request-ID"
+
+	// GinKey 请求 ID 在 gin.Context 中的存储键
+	GinKey = "request_id"
+
+	// maxLength 外部传入请求 ID 的最大长度
+	maxLength = 128
+)
+
+type contextKey struct{}
+
+// New 生成新的请求 ID
+func New() string {
+	return uuid.NewString()
+}
+
+// Valid 校验外部传入的请求 ID
+// 仅允许字母、数字和 -_.:，避免注入日志或 SQL 注释
+func Valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// WithContext 将请求 ID 存入 context
+func WithContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext 从 context 获取请求 ID，不存在时返回空字符串
+func FromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
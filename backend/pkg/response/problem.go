@@ -0,0 +1,81 @@
+package response
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/render"
+
+	"artisan-coder/pkg/i18n"
+)
+
+// ContentTypeProblem RFC 7807 错误响应的媒体类型
+const ContentTypeProblem = "application/problem+json"
+
+// Problem RFC 7807 problem details 结构
+// code、errors、requestId 为扩展成员
+type Problem struct {
+	Type      string            `json:"type"`
+	Title     string            `json:"title"`
+	Status    int               `json:"status"`
+	Detail    string            `json:"detail,omitempty"`
+	Instance  string            `json:"instance,omitempty"`
+	Code      int               `json:"code"`
+	Errors    []i18n.FieldError `json:"errors,omitempty"`
+	RequestID string            `json:"requestId,omitempty"`
+}
+
+// WantsProblem 判断客户端是否通过 Accept 头请求 problem+json
+// 默认仍使用 {code,message,data} 信封，保持现有前端兼容
+func WantsProblem(c *gin.Context) bool {
+	for _, part := range strings.Split(c.GetHeader("Accept"), ",") {
+		mediaType := strings.TrimSpace(strings.SplitN(part, ";", 2)[0])
+		if strings.EqualFold(mediaType, ContentTypeProblem) {
+			return true
+		}
+	}
+	return false
+}
+
+// negotiateProblem 按 Accept 选择错误响应格式，响应随 Accept 变化，缓存需区分
+func negotiateProblem(c *gin.Context) bool {
+	c.Writer.Header().Add("Vary", "Accept")
+	return WantsProblem(c)
+}
+
+// NewProblem 构造 problem details
+// type 为 about:blank，title 为 HTTP 状态短语，具体错误由 code 区分
+func NewProblem(c *gin.Context, statusCode int, code int, detail string) *Problem {
+	return &Problem{
+		Type:      "about:blank",
+		Title:     http.StatusText(statusCode),
+		Status:    statusCode,
+		Detail:    detail,
+		Instance:  c.Request.URL.Path,
+		Code:      code,
+		RequestID: requestID(c),
+	}
+}
+
+// WriteProblem 以 application/problem+json 输出
+func WriteProblem(c *gin.Context, problem *Problem) {
+	c.Render(problem.Status, problemRender{problem: problem})
+}
+
+// problemRender 复用 gin 的 JSON 渲染，仅替换 Content-Type
+type problemRender struct {
+	problem *Problem
+}
+
+func (r problemRender) Render(w http.ResponseWriter) error {
+	r.WriteContentType(w)
+	return render.WriteJSON(w, r.problem)
+}
+
+func (r problemRender) WriteContentType(w http.ResponseWriter) {
+	header := w.Header()
+	if val := header["Content-Type"]; len(val) == 0 {
+		header["Content-Type"] = []string{ContentTypeProblem}
+	}
+}
@@ -4,39 +4,51 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+
+	"artisan-coder/pkg/i18n"
+	"artisan-coder/pkg/requestid"
 )
 
 // Response 统一响应结构
 type Response struct {
-	Code    int         `json:"code"`              // 响应码，0 表示成功
-	Message string      `json:"message"`           // 响应消息
-	Data    interface{} `json:"data"`              // 响应数据，成功时返回数据，失败时为 null
+	Code    int         `json:"code"`    // 响应码，0 表示成功
+	Message string      `json:"message"` // 响应消息
+	Data    interface{} `json:"data"`    // 响应数据，成功时返回数据，失败时为 null
+
+	RequestID string `json:"requestId,omitempty"` // 请求 ID，用于关联日志
 }
 
 const (
-	CodeSuccess        = 0     // 成功
-	CodeBadRequest    = 400   // 请求参数错误
-	CodeUnauthorized  = 401   // 未授权
-	CodeNotFound      = 404   // 资源不存在
-	CodeConflict      = 409   // 资源冲突
-	CodeInternalError = 500   // 服务器内部错误
+	CodeSuccess         = 0   // 成功
+	CodeBadRequest      = 400 // 请求参数错误
+	CodeUnauthorized    = 401 // 未授权
+	CodeForbidden       = 403 // 禁止访问
+	CodeNotFound        = 404 // 资源不存在
+	CodeConflict        = 409 // 资源冲突
+	CodeTooManyRequests = 429 // 请求过于频繁
+	CodeInternalError   = 500 // 服务器内部错误
 )
 
+// 消息 key，输出前按请求语言翻译
 const (
-	MessageSuccess        = "success"
-	MessageBadRequest    = "Bad request"
-	MessageUnauthorized  = "Unauthorized"
-	MessageNotFound      = "Not found"
-	MessageConflict      = "Conflict"
-	MessageInternalError = "Internal server error"
+	MessageSuccess         = i18n.KeySuccess
+	MessageBadRequest      = i18n.KeyBadRequest
+	MessageUnauthorized    = i18n.KeyUnauthorized
+	MessageForbidden       = i18n.KeyForbidden
+	MessageNotFound        = i18n.KeyNotFound
+	MessageConflict        = i18n.KeyConflict
+	MessageTooManyRequests = i18n.KeyTooManyRequests
+	MessageInternalError   = i18n.KeyInternalError
 )
 
 // Success 成功响应 (200)
 func Success(c *gin.Context, data interface{}) {
 	c.JSON(http.StatusOK, Response{
 		Code:    CodeSuccess,
-		Message: MessageSuccess,
+		Message: translate(c, MessageSuccess),
 		Data:    data,
+
+		RequestID: requestID(c),
 	})
 }
 
@@ -44,25 +56,64 @@ func Success(c *gin.Context, data interface{}) {
 func Created(c *gin.Context, data interface{}) {
 	c.JSON(http.StatusCreated, Response{
 		Code:    CodeSuccess,
-		Message: MessageSuccess,
+		Message: translate(c, MessageSuccess),
 		Data:    data,
+
+		RequestID: requestID(c),
 	})
 }
 
 // Error 错误响应
+// message 为消息 key，未登记的 key 原样输出
+// 客户端 Accept 为 application/problem+json 时输出 RFC 7807 格式
 func Error(c *gin.Context, statusCode int, code int, message string) {
+	if negotiateProblem(c) {
+		WriteProblem(c, NewProblem(c, statusCode, code, translate(c, message)))
+		return
+	}
+
 	c.JSON(statusCode, Response{
 		Code:    code,
-		Message: message,
+		Message: translate(c, message),
 		Data:    nil,
+
+		RequestID: requestID(c),
 	})
 }
 
 // BadRequest 400 错误
 func BadRequest(c *gin.Context, message string) {
+	if message == "" {
+		message = MessageBadRequest
+	}
 	Error(c, http.StatusBadRequest, CodeBadRequest, message)
 }
 
+// ValidationError 参数绑定/校验失败 (400)
+// 校验错误按请求语言翻译，逐字段放入 data
+func ValidationError(c *gin.Context, err error) {
+	fields := i18n.TranslateValidationErrors(Locale(c), err)
+	if fields == nil {
+		BadRequest(c, i18n.KeyInvalidRequestBody)
+		return
+	}
+
+	if negotiateProblem(c) {
+		problem := NewProblem(c, http.StatusBadRequest, CodeBadRequest, translate(c, i18n.KeyValidationFailed))
+		problem.Errors = fields
+		WriteProblem(c, problem)
+		return
+	}
+
+	c.JSON(http.StatusBadRequest, Response{
+		Code:    CodeBadRequest,
+		Message: translate(c, i18n.KeyValidationFailed),
+		Data:    fields,
+
+		RequestID: requestID(c),
+	})
+}
+
 // Unauthorized 401 错误
 func Unauthorized(c *gin.Context, message string) {
 	if message == "" {
@@ -71,6 +122,14 @@ func Unauthorized(c *gin.Context, message string) {
 	Error(c, http.StatusUnauthorized, CodeUnauthorized, message)
 }
 
+// Forbidden 403 错误
+func Forbidden(c *gin.Context, message string) {
+	if message == "" {
+		message = MessageForbidden
+	}
+	Error(c, http.StatusForbidden, CodeForbidden, message)
+}
+
 // NotFound 404 错误
 func NotFound(c *gin.Context, message string) {
 	if message == "" {
@@ -87,7 +146,33 @@ func Conflict(c *gin.Context, message string) {
 	Error(c, http.StatusConflict, CodeConflict, message)
 }
 
+// TooManyRequests 429 错误
+func TooManyRequests(c *gin.Context, message string) {
+	if message == "" {
+		message = MessageTooManyRequests
+	}
+	Error(c, http.StatusTooManyRequests, CodeTooManyRequests, message)
+}
+
 // InternalError 500 错误
 func InternalError(c *gin.Context) {
 	Error(c, http.StatusInternalServerError, CodeInternalError, MessageInternalError)
 }
+
+// Locale 返回当前请求协商出的语言
+func Locale(c *gin.Context) i18n.Locale {
+	if v, ok := c.Get(i18n.GinKey); ok {
+		if locale, ok := v.(i18n.Locale); ok {
+			return locale
+		}
+	}
+	return i18n.DefaultLocale
+}
+
+func translate(c *gin.Context, key string) string {
+	return i18n.T(Locale(c), key)
+}
+
+func requestID(c *gin.Context) string {
+	return c.GetString(requestid.GinKey)
+}
@@ -0,0 +1,52 @@
+package version
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// 构建时通过 -ldflags 注入：
+//
+//	go build -ldflags "-X artisan-coder/pkg/version.Version=v1.0.0 -X artisan-coder/pkg/version.Commit=$(git rev-parse HEAD)"
+var (
+	Version   = "dev"
+	Commit    = ""
+	BuildTime = ""
+)
+
+// Info 构建与版本信息
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"buildTime"`
+	GoVersion string `json:"goVersion"`
+	Platform  string `json:"platform"`
+}
+
+// Get 返回版本信息，未注入时从 Go 构建信息中补全 VCS 字段
+func Get() Info {
+	info := Info{
+		Version:   Version,
+		Commit:    Commit,
+		BuildTime: BuildTime,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+	}
+
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, s := range bi.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				if info.Commit == "" {
+					info.Commit = s.Value
+				}
+			case "vcs.time":
+				if info.BuildTime == "" {
+					info.BuildTime = s.Value
+				}
+			}
+		}
+	}
+
+	return info
+}